@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    pinecone = {
+      source = "pinecone-io/pinecone"
+    }
+  }
+}
+
+provider "pinecone" {
+  validate_credentials = true
+}
+
+data "pinecone_caller_identity" "current" {}
+
+output "project_id" {
+  value = data.pinecone_caller_identity.current.project_id
+}
@@ -11,3 +11,9 @@ provider "pinecone" {}
 data "pinecone_collections" "test" {
 }
 
+
+data "pinecone_collections" "ready" {
+  name_regex = "^search-"
+  status     = "Ready"
+  dimension  = 1536
+}
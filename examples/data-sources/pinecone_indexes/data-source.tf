@@ -21,4 +21,10 @@ resource "pinecone_index" "test" {
 }
 
 data "pinecone_indexes" "test" {
-}
\ No newline at end of file
+}
+data "pinecone_indexes" "search" {
+  name_prefix = "search-"
+  spec_type   = "serverless"
+  region      = "us-west-2"
+  ready_only  = true
+}
@@ -0,0 +1,16 @@
+locals {
+  # { pod_type = "s1.x8", pods = 5 }
+  sizing = provider::pinecone::estimate_storage(100000000, 1536, 0)
+}
+
+resource "pinecone_index" "example" {
+  name      = "docs-example"
+  dimension = 1536
+  spec = {
+    pod = {
+      environment = "us-west4-gcp"
+      pod_type    = local.sizing.pod_type
+      shards      = local.sizing.pods
+    }
+  }
+}
@@ -0,0 +1,14 @@
+resource "pinecone_index" "example" {
+  name      = "docs-example"
+  dimension = 1536
+  spec = {
+    serverless = {
+      cloud  = "aws"
+      region = "us-east-1"
+    }
+  }
+}
+
+output "project_id" {
+  value = provider::pinecone::index_host_parts(pinecone_index.example.host).project_id
+}
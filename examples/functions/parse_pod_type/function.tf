@@ -0,0 +1,4 @@
+output "pod_family" {
+  # "p1"
+  value = provider::pinecone::parse_pod_type("p1.x2").family
+}
@@ -0,0 +1,9 @@
+locals {
+  shards   = 2
+  replicas = 3
+}
+
+output "pods" {
+  # 6
+  value = provider::pinecone::pod_count(local.shards, local.replicas)
+}
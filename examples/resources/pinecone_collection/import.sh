@@ -0,0 +1,3 @@
+# Import a collection by its name or a project_id/name pair.
+terraform import pinecone_collection.example products-backup
+terraform import pinecone_collection.example 4zo0ijk/products-backup
@@ -0,0 +1,4 @@
+# Import an index by its name, a project_id/name pair, or its host.
+terraform import pinecone_index.example products
+terraform import pinecone_index.example 4zo0ijk/products
+terraform import pinecone_index.example products-4zo0ijk.svc.us-west4-gcp.pinecone.io
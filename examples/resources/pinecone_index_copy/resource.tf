@@ -0,0 +1,33 @@
+terraform {
+  required_providers {
+    pinecone = {
+      source = "pinecone-io/pinecone"
+    }
+  }
+}
+
+provider "pinecone" {}
+
+resource "pinecone_index" "staging" {
+  name      = "tftestindex-staging"
+  dimension = 1536
+  spec = {
+    serverless = {
+      cloud  = "aws"
+      region = "eu-west-1"
+    }
+  }
+}
+
+resource "pinecone_index_copy" "staging" {
+  source_index       = "tftestindex-prod"
+  target_index       = pinecone_index.staging.name
+  exclude_namespaces = ["pii"]
+  metadata_filter = jsonencode({
+    environment = { "$ne" = "internal" }
+  })
+
+  timeouts {
+    create = "2h"
+  }
+}
@@ -0,0 +1,31 @@
+terraform {
+  required_providers {
+    pinecone = {
+      source = "pinecone-io/pinecone"
+    }
+  }
+}
+
+provider "pinecone" {}
+
+resource "pinecone_index" "test" {
+  name      = "tftestindex"
+  dimension = 10
+  spec = {
+    pod = {
+      environment = "us-west4-gcp"
+      pod_type    = "s1.x1"
+    }
+  }
+}
+
+resource "pinecone_index_migration" "test" {
+  source_index  = pinecone_index.test.name
+  target_name   = "tftestindex-serverless"
+  target_cloud  = "aws"
+  target_region = "us-east-1"
+}
+
+output "serverless_host" {
+  value = pinecone_index_migration.test.target_host
+}
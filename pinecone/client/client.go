@@ -0,0 +1,282 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package client defines the parts of the Pinecone API the provider depends on, so that
+// resources and data sources can be exercised against an in-memory implementation.
+//
+// The Go client sends its control plane requests through http.DefaultTransport and
+// cannot be given an HTTP client of its own. To retry, rate limit and proxy those
+// requests, New therefore wraps http.DefaultTransport of the whole process in a
+// Transport. The wrapper only acts on requests whose context carries the Options of a
+// ControlPlane; any other request in the process, from other code or another client,
+// is handed to the original transport unchanged.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+// ControlPlane is the subset of the Pinecone control plane used by the provider.
+type ControlPlane interface {
+	ListIndexes(ctx context.Context) ([]*pinecone.Index, error)
+	CreatePodIndex(ctx context.Context, in *pinecone.CreatePodIndexRequest) (*pinecone.Index, error)
+	CreateServerlessIndex(ctx context.Context, in *pinecone.CreateServerlessIndexRequest) (*pinecone.Index, error)
+	DescribeIndex(ctx context.Context, name string) (*pinecone.Index, error)
+	DeleteIndex(ctx context.Context, name string) error
+	// CreateServerlessIndexFromCollection creates a serverless index holding the vectors
+	// of a collection, which is how pod indexes are migrated to serverless.
+	CreateServerlessIndexFromCollection(ctx context.Context, in *pinecone.CreateServerlessIndexRequest, sourceCollection string) (*pinecone.Index, error)
+
+	ListCollections(ctx context.Context) ([]*pinecone.Collection, error)
+	CreateCollection(ctx context.Context, in *pinecone.CreateCollectionRequest) (*pinecone.Collection, error)
+	DescribeCollection(ctx context.Context, name string) (*pinecone.Collection, error)
+	DeleteCollection(ctx context.Context, name string) error
+
+	// Index connects to the data plane of the index served at host.
+	// The connection must be closed once it is no longer needed.
+	Index(ctx context.Context, host string) (DataPlane, error)
+}
+
+// DataPlane is the subset of the data plane of a single index used by the provider.
+type DataPlane interface {
+	DescribeIndexStats(ctx context.Context) (*pinecone.DescribeIndexStatsResponse, error)
+	ListVectors(ctx context.Context, namespace string, in *pinecone.ListVectorsRequest) (*pinecone.ListVectorsResponse, error)
+	FetchVectors(ctx context.Context, namespace string, ids []string) (*pinecone.FetchVectorsResponse, error)
+	UpsertVectors(ctx context.Context, namespace string, vectors []*pinecone.Vector) (uint32, error)
+	Close() error
+}
+
+// controlPlaneURL is the address of the control plane API, which the Go client is
+// hard-wired to as well.
+const controlPlaneURL = "https://api.pinecone.io"
+
+// New returns a ControlPlane backed by the Pinecone API that sends its requests
+// according to options. apiKey must be the key c was created with.
+func New(c *pinecone.Client, apiKey string, options Options) ControlPlane {
+	installTransport()
+	options.prepare()
+	return Coalesce(&pineconeClient{
+		client:  c,
+		options: options,
+		apiKey:  apiKey,
+		url:     controlPlaneURL,
+		http:    &http.Client{Transport: &Transport{Base: originalTransport}},
+	})
+}
+
+type pineconeClient struct {
+	client  *pinecone.Client
+	options Options
+
+	// apiKey, url and http serve the requests the Go client does not support. http
+	// has a Transport of its own rather than relying on the wrapped
+	// http.DefaultTransport.
+	apiKey string
+	url    string
+	http   *http.Client
+}
+
+// context prepares the context of a control plane call.
+func (c *pineconeClient) context(ctx context.Context) context.Context {
+	return withOptions(ctx, &c.options)
+}
+
+var _ ControlPlane = &pineconeClient{}
+
+func (c *pineconeClient) ListIndexes(ctx context.Context) ([]*pinecone.Index, error) {
+	return c.client.ListIndexes(c.context(ctx))
+}
+
+func (c *pineconeClient) CreatePodIndex(ctx context.Context, in *pinecone.CreatePodIndexRequest) (*pinecone.Index, error) {
+	return c.client.CreatePodIndex(c.context(ctx), in)
+}
+
+func (c *pineconeClient) CreateServerlessIndex(ctx context.Context, in *pinecone.CreateServerlessIndexRequest) (*pinecone.Index, error) {
+	return c.client.CreateServerlessIndex(c.context(ctx), in)
+}
+
+func (c *pineconeClient) DescribeIndex(ctx context.Context, name string) (*pinecone.Index, error) {
+	return c.client.DescribeIndex(c.context(ctx), name)
+}
+
+func (c *pineconeClient) DeleteIndex(ctx context.Context, name string) error {
+	return c.client.DeleteIndex(c.context(ctx), name)
+}
+
+// CreateServerlessIndexFromCollection sends the request itself, as the Go client cannot
+// set the source collection of a serverless index. It is retried, rate limited and
+// proxied like every other request.
+func (c *pineconeClient) CreateServerlessIndexFromCollection(ctx context.Context, in *pinecone.CreateServerlessIndexRequest, sourceCollection string) (*pinecone.Index, error) {
+	metric := in.Metric
+	if metric == "" {
+		metric = pinecone.Cosine
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"name":      in.Name,
+		"dimension": in.Dimension,
+		"metric":    metric,
+		"spec": map[string]interface{}{
+			"serverless": map[string]interface{}{
+				"cloud":             in.Cloud,
+				"region":            in.Region,
+				"source_collection": sourceCollection,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(c.context(ctx), http.MethodPost, c.url+"/indexes", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Api-Key", c.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		// Match the errors of the Go client, which the provider classifies by message.
+		var errResp struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil || errResp.Error.Message == "" {
+			return nil, fmt.Errorf("failed to create index: unexpected status code: %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to create index: %s", errResp.Error.Message)
+	}
+
+	var index struct {
+		Name      string               `json:"name"`
+		Dimension int32                `json:"dimension"`
+		Metric    pinecone.IndexMetric `json:"metric"`
+		Host      string               `json:"host"`
+		Spec      struct {
+			Serverless *pinecone.ServerlessSpec `json:"serverless"`
+		} `json:"spec"`
+		Status *struct {
+			Ready bool                      `json:"ready"`
+			State pinecone.IndexStatusState `json:"state"`
+		} `json:"status"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to decode index response: %w", err)
+	}
+	created := &pinecone.Index{
+		Name:      index.Name,
+		Dimension: index.Dimension,
+		Metric:    index.Metric,
+		Host:      index.Host,
+		Spec:      &pinecone.IndexSpec{Serverless: index.Spec.Serverless},
+	}
+	if index.Status != nil {
+		created.Status = &pinecone.IndexStatus{Ready: index.Status.Ready, State: index.Status.State}
+	}
+	return created, nil
+}
+
+func (c *pineconeClient) ListCollections(ctx context.Context) ([]*pinecone.Collection, error) {
+	return c.client.ListCollections(c.context(ctx))
+}
+
+func (c *pineconeClient) CreateCollection(ctx context.Context, in *pinecone.CreateCollectionRequest) (*pinecone.Collection, error) {
+	return c.client.CreateCollection(c.context(ctx), in)
+}
+
+func (c *pineconeClient) DescribeCollection(ctx context.Context, name string) (*pinecone.Collection, error) {
+	return c.client.DescribeCollection(c.context(ctx), name)
+}
+
+func (c *pineconeClient) DeleteCollection(ctx context.Context, name string) error {
+	return c.client.DeleteCollection(c.context(ctx), name)
+}
+
+func (c *pineconeClient) Index(ctx context.Context, host string) (DataPlane, error) {
+	return &indexConnection{client: c.client, host: host, namespaces: map[string]*pinecone.IndexConnection{}}, nil
+}
+
+// indexConnection opens one connection per namespace, as the Go client binds a
+// connection to a single namespace.
+type indexConnection struct {
+	client *pinecone.Client
+	host   string
+
+	mu         sync.Mutex
+	namespaces map[string]*pinecone.IndexConnection
+}
+
+var _ DataPlane = &indexConnection{}
+
+func (c *indexConnection) namespace(namespace string) (*pinecone.IndexConnection, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if conn, ok := c.namespaces[namespace]; ok {
+		return conn, nil
+	}
+	conn, err := c.client.IndexWithNamespace(c.host, namespace)
+	if err != nil {
+		return nil, err
+	}
+	c.namespaces[namespace] = conn
+	return conn, nil
+}
+
+func (c *indexConnection) DescribeIndexStats(ctx context.Context) (*pinecone.DescribeIndexStatsResponse, error) {
+	conn, err := c.namespace("")
+	if err != nil {
+		return nil, err
+	}
+	return conn.DescribeIndexStats(&ctx)
+}
+
+func (c *indexConnection) ListVectors(ctx context.Context, namespace string, in *pinecone.ListVectorsRequest) (*pinecone.ListVectorsResponse, error) {
+	conn, err := c.namespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return conn.ListVectors(&ctx, in)
+}
+
+func (c *indexConnection) FetchVectors(ctx context.Context, namespace string, ids []string) (*pinecone.FetchVectorsResponse, error) {
+	conn, err := c.namespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return conn.FetchVectors(&ctx, ids)
+}
+
+func (c *indexConnection) UpsertVectors(ctx context.Context, namespace string, vectors []*pinecone.Vector) (uint32, error) {
+	conn, err := c.namespace(namespace)
+	if err != nil {
+		return 0, err
+	}
+	return conn.UpsertVectors(&ctx, vectors)
+}
+
+func (c *indexConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var firstErr error
+	for namespace, conn := range c.namespaces {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(c.namespaces, namespace)
+	}
+	return firstErr
+}
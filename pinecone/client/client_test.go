@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+func TestPineconeClient_CreateServerlessIndexFromCollection(t *testing.T) {
+	var got map[string]interface{}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/indexes" || r.Header.Get("Api-Key") != "key" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		if got["name"] == "existing" {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"code":"ALREADY_EXISTS","message":"Resource existing already exists"},"status":409}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"name":"migrated","dimension":8,"metric":"cosine","host":"migrated-abc.svc.aws-us-east-1.pinecone.io",
+			"spec":{"serverless":{"cloud":"aws","region":"us-east-1"}},"status":{"ready":false,"state":"Initializing"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	c := &pineconeClient{
+		options: Options{Retry: RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}},
+		apiKey:  "key",
+		url:     server.URL,
+		http:    &http.Client{Transport: &Transport{Base: http.DefaultTransport}},
+	}
+	in := &pinecone.CreateServerlessIndexRequest{Name: "migrated", Dimension: 8, Cloud: pinecone.Aws, Region: "us-east-1"}
+	index, err := c.CreateServerlessIndexFromCollection(context.Background(), in, "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverless, _ := got["spec"].(map[string]interface{})["serverless"].(map[string]interface{})
+	if serverless["source_collection"] != "snapshot" || serverless["region"] != "us-east-1" || got["metric"] != "cosine" {
+		t.Errorf("unexpected request body %v", got)
+	}
+	if requests != 2 {
+		t.Errorf("expected the rate-limited request to be retried, got %d requests", requests)
+	}
+	if index.Host != "migrated-abc.svc.aws-us-east-1.pinecone.io" || index.Spec.Serverless.Region != "us-east-1" || index.Status.State != pinecone.Initializing {
+		t.Errorf("unexpected index %+v", index)
+	}
+
+	in.Name = "existing"
+	if _, err := c.CreateServerlessIndexFromCollection(context.Background(), in, "snapshot"); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected the API error message, got: %v", err)
+	}
+}
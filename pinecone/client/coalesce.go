@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+// Coalesce wraps c so that concurrent DescribeIndex calls for the same index share a
+// single request. Waiters of different resources, or a resource and a data source,
+// often poll the same index at the same time.
+func Coalesce(c ControlPlane) ControlPlane {
+	return &coalescingControlPlane{ControlPlane: c, describes: map[string]*describeCall{}}
+}
+
+type coalescingControlPlane struct {
+	ControlPlane
+
+	mu        sync.Mutex
+	describes map[string]*describeCall
+}
+
+type describeCall struct {
+	done  chan struct{}
+	index *pinecone.Index
+	err   error
+	// cancelled is set when the context of the caller that made the call ended, in
+	// which case its error is not that of the callers who joined it.
+	cancelled bool
+}
+
+func (c *coalescingControlPlane) DescribeIndex(ctx context.Context, name string) (*pinecone.Index, error) {
+	for {
+		c.mu.Lock()
+		call, ok := c.describes[name]
+		if !ok {
+			call = &describeCall{done: make(chan struct{})}
+			c.describes[name] = call
+			c.mu.Unlock()
+			return c.describe(ctx, name, call)
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.cancelled {
+			continue
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		return copyIndex(call.index), nil
+	}
+}
+
+// describe makes call on behalf of every caller that joins it while in flight.
+func (c *coalescingControlPlane) describe(ctx context.Context, name string, call *describeCall) (*pinecone.Index, error) {
+	call.index, call.err = c.ControlPlane.DescribeIndex(ctx, name)
+	call.cancelled = call.err != nil && ctx.Err() != nil
+
+	c.mu.Lock()
+	delete(c.describes, name)
+	c.mu.Unlock()
+	close(call.done)
+
+	if call.err != nil {
+		return nil, call.err
+	}
+	return copyIndex(call.index), nil
+}
+
+// copyIndex returns a copy of index that callers can modify without affecting others.
+func copyIndex(index *pinecone.Index) *pinecone.Index {
+	c := *index
+	if index.Status != nil {
+		status := *index.Status
+		c.Status = &status
+	}
+	return &c
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+func TestCoalesce_DescribeIndex(t *testing.T) {
+	m := NewMemory()
+	m.PutIndex(&pinecone.Index{Name: "test", Status: &pinecone.IndexStatus{Ready: true, State: pinecone.Ready}})
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var once sync.Once
+	m.Hook = func(op Operation, name string) error {
+		if op == OpDescribeIndex {
+			once.Do(func() { close(started) })
+			<-unblock
+		}
+		return nil
+	}
+	c := Coalesce(m)
+
+	const callers = 5
+	results := make([]*pinecone.Index, callers)
+	var wg sync.WaitGroup
+	describe := func(i int) {
+		defer wg.Done()
+		index, err := c.DescribeIndex(context.Background(), "test")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		results[i] = index
+	}
+
+	wg.Add(1)
+	go describe(0)
+	<-started
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go describe(i)
+	}
+	// Give the followers time to join the call in flight.
+	time.Sleep(20 * time.Millisecond)
+	close(unblock)
+	wg.Wait()
+
+	if got := m.Calls(OpDescribeIndex); got != 1 {
+		t.Errorf("expected the callers to share 1 describe, got %d", got)
+	}
+	for i, index := range results {
+		if index == nil || index.Name != "test" {
+			t.Fatalf("caller %d: unexpected index %+v", i, index)
+		}
+	}
+	results[0].Status.State = pinecone.Terminating
+	if results[1].Status.State != pinecone.Ready {
+		t.Error("expected every caller to receive its own copy of the index")
+	}
+}
+
+func TestCoalesce_DescribeIndex_sequential(t *testing.T) {
+	m := NewMemory()
+	m.PutIndex(&pinecone.Index{Name: "test", Status: &pinecone.IndexStatus{State: pinecone.Initializing}})
+	c := Coalesce(m)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.DescribeIndex(context.Background(), "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Polls one after the other must each see the latest state.
+	if got := m.Calls(OpDescribeIndex); got != 2 {
+		t.Errorf("expected 2 describes, got %d", got)
+	}
+	if _, err := c.DescribeIndex(context.Background(), "missing"); err == nil {
+		t.Error("expected the error of the shared call")
+	}
+}
+
+func TestCoalesce_DescribeIndex_leaderCancelled(t *testing.T) {
+	m := NewMemory()
+	m.PutIndex(&pinecone.Index{Name: "test", Status: &pinecone.IndexStatus{Ready: true, State: pinecone.Ready}})
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var once sync.Once
+	m.Hook = func(op Operation, name string) error {
+		first := false
+		once.Do(func() { first = true })
+		if !first {
+			return nil
+		}
+		// The first describe is aborted along with the context of its caller.
+		close(started)
+		<-unblock
+		return context.Canceled
+	}
+	c := Coalesce(m)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error)
+	go func() {
+		_, err := c.DescribeIndex(ctx, "test")
+		leader <- err
+	}()
+	<-started
+
+	follower := make(chan error)
+	go func() {
+		_, err := c.DescribeIndex(context.Background(), "test")
+		follower <- err
+	}()
+	// Give the follower time to join the call in flight.
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	close(unblock)
+
+	if err := <-leader; err == nil {
+		t.Error("expected the cancelled caller to fail")
+	}
+	if err := <-follower; err != nil {
+		t.Errorf("expected the follower to describe the index again, got: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter caps the number of control plane requests in flight and the rate at which
+// they are sent. A single Limiter is shared by every resource and data source of a
+// provider, so that Terraform's parallelism does not translate into bursts of requests.
+// A nil Limiter does not limit anything.
+type Limiter struct {
+	// slots holds a token for every request in flight. It is nil when concurrency is unlimited.
+	slots chan struct{}
+	// interval is the minimum time between the start of two requests, zero when unlimited.
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewLimiter returns a Limiter allowing maxConcurrent requests in flight and
+// requestsPerSecond requests to start every second. Zero disables either limit.
+func NewLimiter(maxConcurrent int, requestsPerSecond float64) *Limiter {
+	if maxConcurrent <= 0 && requestsPerSecond <= 0 {
+		return nil
+	}
+
+	l := &Limiter{now: time.Now, sleep: sleepContext}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if requestsPerSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return l
+}
+
+// Acquire blocks until a request may be sent or ctx is done. The returned function
+// must be called once the request has completed.
+func (l *Limiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release = func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if delay := l.reserve(); delay > 0 {
+		if err := l.sleep(ctx, delay); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// reserve claims the next start time and returns how long to wait for it. Requests
+// are spaced evenly rather than allowed to burst.
+func (l *Limiter) reserve() time.Duration {
+	if l.interval == 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	return start.Sub(now)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
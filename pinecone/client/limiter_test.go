@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewLimiter_unlimited(t *testing.T) {
+	l := NewLimiter(0, 0)
+	if l != nil {
+		t.Fatalf("expected no limiter, got %+v", l)
+	}
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
+
+func TestLimiter_concurrency(t *testing.T) {
+	l := NewLimiter(2, 0)
+
+	var inFlight, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.Acquire(context.Background())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			release()
+		}()
+	}
+	wg.Wait()
+
+	if peak != 2 {
+		t.Errorf("expected at most 2 requests in flight, got %d", peak)
+	}
+}
+
+func TestLimiter_rate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var delays []time.Duration
+
+	l := NewLimiter(0, 4)
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	// After a pause longer than the interval, the next request starts right away.
+	now = now.Add(time.Second)
+	release, _ := l.Acquire(context.Background())
+	release()
+
+	want := []time.Duration{250 * time.Millisecond, 500 * time.Millisecond}
+	if len(delays) != len(want) || delays[0] != want[0] || delays[1] != want[1] {
+		t.Errorf("expected delays %v, got %v", want, delays)
+	}
+}
+
+func TestLimiter_contextCancelled(t *testing.T) {
+	l := NewLimiter(1, 0)
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.Acquire(ctx); err == nil {
+		t.Error("expected to give up waiting for a slot when the context is done")
+	}
+}
+
+func TestTransport_limiter(t *testing.T) {
+	server, bodies := testServer(t, nil, 503)
+	var delays []time.Duration
+
+	// The retry takes a slot of its own, so a single slot must be released in between.
+	options := &Options{Retry: RetryPolicy{MaxRetries: 1, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}, Limiter: NewLimiter(1, 0)}
+	if got := send(t, testTransport(&delays), options, http.MethodGet, server.URL, ""); got != 200 {
+		t.Errorf("expected status 200, got %d", got)
+	}
+	if len(*bodies) != 2 {
+		t.Errorf("expected 2 requests, got %d", len(*bodies))
+	}
+	if len(options.Limiter.slots) != 0 {
+		t.Errorf("expected every slot to be released, %d are held", len(options.Limiter.slots))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+func TestMemory_indexLifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	m.ReadyAfter = 2
+
+	created, err := m.CreateServerlessIndex(ctx, &pinecone.CreateServerlessIndexRequest{Name: "test", Dimension: 8, Cloud: pinecone.Aws, Region: "us-west-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Status.State != pinecone.Initializing || created.Metric != pinecone.Cosine {
+		t.Errorf("unexpected index: %+v", created)
+	}
+
+	if _, err := m.CreateServerlessIndex(ctx, &pinecone.CreateServerlessIndexRequest{Name: "test"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected a conflict, got: %v", err)
+	}
+
+	for i, want := range []pinecone.IndexStatusState{pinecone.Initializing, pinecone.Ready} {
+		index, err := m.DescribeIndex(ctx, "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index.Status.State != want {
+			t.Errorf("describe %d: expected %s, got %s", i+1, want, index.Status.State)
+		}
+	}
+
+	if err := m.DeleteIndex(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.DescribeIndex(ctx, "test"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected the index to be gone, got: %v", err)
+	}
+	if got := m.Calls(OpDescribeIndex); got != 3 {
+		t.Errorf("expected 3 describes, got %d", got)
+	}
+}
+
+func TestMemory_Hook(t *testing.T) {
+	m := NewMemory()
+	m.Hook = func(op Operation, name string) error {
+		if op == OpListIndexes {
+			return errors.New("unexpected status code: 503")
+		}
+		return nil
+	}
+
+	if _, err := m.ListIndexes(context.Background()); err == nil {
+		t.Error("expected the hook error")
+	}
+	if _, err := m.ListCollections(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMemory_collectionFromIndex(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	index, err := m.CreatePodIndex(ctx, &pinecone.CreatePodIndexRequest{Name: "source", Dimension: 4, Environment: "us-west4-gcp", PodType: "s1.x1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.PutVectors(index.Host, "ns", &pinecone.Vector{Id: "a"}, &pinecone.Vector{Id: "b"})
+
+	collection, err := m.CreateCollection(ctx, &pinecone.CreateCollectionRequest{Name: "backup", Source: "source"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *collection.VectorCount != 2 || *collection.Dimension != 4 || collection.Environment != "us-west4-gcp" {
+		t.Errorf("unexpected collection: %+v", collection)
+	}
+
+	restored, err := m.CreatePodIndex(ctx, &pinecone.CreatePodIndexRequest{Name: "restored", Dimension: 4, Environment: "us-west4-gcp", PodType: "s1.x1", SourceCollection: &collection.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := m.Index(ctx, restored.Host)
+	stats, err := data.DescribeIndexStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalVectorCount != 2 || stats.Namespaces["ns"].VectorCount != 2 || stats.Dimension != 4 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	migrated, err := m.CreateServerlessIndexFromCollection(ctx, &pinecone.CreateServerlessIndexRequest{Name: "migrated", Dimension: 4, Cloud: pinecone.Aws, Region: "us-east-1"}, collection.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = m.Index(ctx, migrated.Host)
+	if stats, _ := data.DescribeIndexStats(ctx); stats.Namespaces["ns"].VectorCount != 2 {
+		t.Errorf("expected the vectors of the collection in the serverless index, got %+v", stats)
+	}
+}
+
+func TestMemory_ListVectors(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+	for i := 0; i < 5; i++ {
+		m.PutVectors("host", "", &pinecone.Vector{Id: fmt.Sprintf("id-%d", i)})
+	}
+	m.PutVectors("host", "", &pinecone.Vector{Id: "other"})
+	data, _ := m.Index(ctx, "host")
+
+	prefix := "id-"
+	limit := uint32(2)
+	var pages [][]string
+	var token *string
+	for {
+		resp, err := data.ListVectors(ctx, "", &pinecone.ListVectorsRequest{Prefix: &prefix, Limit: &limit, PaginationToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var page []string
+		for _, id := range resp.VectorIds {
+			page = append(page, *id)
+		}
+		pages = append(pages, page)
+		if resp.NextPaginationToken == nil {
+			break
+		}
+		token = resp.NextPaginationToken
+	}
+
+	expected := [][]string{{"id-0", "id-1"}, {"id-2", "id-3"}, {"id-4"}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("expected pages %v, got %v", expected, pages)
+	}
+}
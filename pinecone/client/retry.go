@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries      = 3
+	DefaultRetryMinBackoff = 1 * time.Second
+	DefaultRetryMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy configures how failed control plane requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+	// MinBackoff is the delay before the first retry. It doubles with every retry up to MaxBackoff.
+	MinBackoff time.Duration
+	// MaxBackoff also caps the delay a response asks for with Retry-After.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used when the provider does not override it.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultRetryMinBackoff,
+		MaxBackoff: DefaultRetryMaxBackoff,
+	}
+}
+
+// Validate reports whether the policy can be used.
+func (p RetryPolicy) Validate() error {
+	switch {
+	case p.MaxRetries < 0:
+		return errors.New("max_retries must not be negative")
+	case p.MinBackoff <= 0:
+		return errors.New("retry_min_backoff must be greater than zero")
+	case p.MaxBackoff < p.MinBackoff:
+		return errors.New("retry_max_backoff must be greater than or equal to retry_min_backoff")
+	}
+	return nil
+}
+
+// backoff returns the delay before the given retry, counting from zero. Half of the
+// delay is randomised so that concurrent operations do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.MinBackoff
+	for i := 0; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// shouldRetry decides whether a request can be retried after it failed with err or
+// received resp.
+//
+// Requests that cannot change anything, as well as DELETE, are retried on rate limits,
+// server errors and network errors. Other requests, such as the POST that creates an
+// index or collection, are only retried when they cannot have been acted on: after a 429
+// response, or when the connection could not be established at all. A 503 may come from
+// a proxy or load balancer after the API already created the object, so it is not enough.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body has been consumed and cannot be sent again.
+		return false
+	}
+
+	idempotent := isIdempotent(req.Method)
+	if err != nil {
+		if idempotent {
+			return true
+		}
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial"
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header of resp, which holds either a number of
+// seconds or an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// describeAttempt summarises the outcome of an attempt for log messages.
+func describeAttempt(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("status %d", resp.StatusCode)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Options configure how a ControlPlane sends its requests.
+type Options struct {
+	Retry RetryPolicy
+	// Limiter is shared by every ControlPlane of a provider. It may be nil.
+	Limiter *Limiter
+
+	// Proxy is the URL of the proxy requests are sent through. When nil, the proxy is
+	// taken from the HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy *url.URL
+	// RootCAs verify the certificate of the API instead of the system pool when set.
+	RootCAs *x509.CertPool
+	// Headers are added to every request.
+	Headers http.Header
+	// UserAgent is prepended to the user agent of the Go client.
+	UserAgent string
+
+	// base sends the requests when Proxy or RootCAs require a dedicated transport.
+	base http.RoundTripper
+}
+
+// prepare sets up the transport that Options require. It is called once by New.
+func (o *Options) prepare() {
+	if o.Proxy == nil && o.RootCAs == nil {
+		return
+	}
+
+	var t *http.Transport
+	if original, ok := originalTransport.(*http.Transport); ok {
+		t = original.Clone()
+	} else {
+		t = &http.Transport{Proxy: http.ProxyFromEnvironment}
+	}
+	if o.Proxy != nil {
+		t.Proxy = http.ProxyURL(o.Proxy)
+	}
+	if o.RootCAs != nil {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.RootCAs = o.RootCAs
+	}
+	o.base = t
+}
+
+// request returns req with the headers and user agent of the Options applied.
+func (o *Options) request(req *http.Request) *http.Request {
+	if len(o.Headers) == 0 && o.UserAgent == "" {
+		return req
+	}
+
+	req = req.Clone(req.Context())
+	for name, values := range o.Headers {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	if o.UserAgent != "" {
+		req.Header.Set("User-Agent", strings.TrimSpace(o.UserAgent+" "+req.Header.Get("User-Agent")))
+	}
+	return req
+}
+
+type optionsKey struct{}
+
+// withOptions attaches options to the context of a control plane call, where the
+// Transport picks them up.
+func withOptions(ctx context.Context, options *Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, options)
+}
+
+func optionsFromContext(ctx context.Context) (*Options, bool) {
+	options, ok := ctx.Value(optionsKey{}).(*Options)
+	return options, ok
+}
+
+// Transport applies the Options of a control plane call to its HTTP requests. The Go
+// client does not accept an HTTP client and always sends its requests through
+// http.DefaultTransport, so installTransport wraps that instead. Requests without
+// Options, i.e. those not made through a ControlPlane, are passed through unchanged.
+type Transport struct {
+	Base http.RoundTripper
+
+	// sleep waits between retries. It is replaced in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+var (
+	installOnce sync.Once
+	// originalTransport is http.DefaultTransport before it was wrapped.
+	originalTransport http.RoundTripper = http.DefaultTransport
+)
+
+// installTransport wraps http.DefaultTransport in a Transport once per process.
+func installTransport() {
+	installOnce.Do(func() {
+		originalTransport = http.DefaultTransport
+		http.DefaultTransport = &Transport{Base: originalTransport}
+	})
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	options, ok := optionsFromContext(req.Context())
+	if !ok {
+		return t.Base.RoundTrip(req)
+	}
+	return t.roundTripWithRetries(req, options)
+}
+
+func (t *Transport) roundTripWithRetries(req *http.Request, options *Options) (*http.Response, error) {
+	ctx := req.Context()
+	policy := options.Retry
+	req = options.request(req)
+	base := t.Base
+	if options.base != nil {
+		base = options.base
+	}
+
+	for retry := 0; ; retry++ {
+		attempt := req
+		if retry > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt = req.Clone(ctx)
+			attempt.Body = body
+		}
+
+		// Every attempt counts against the limits, but waiting between retries does not.
+		release, err := options.Limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := base.RoundTrip(attempt)
+		release()
+
+		if retry >= policy.MaxRetries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		delay := policy.backoff(retry)
+		if after, ok := retryAfter(resp, time.Now()); ok {
+			// A Retry-After beyond MaxBackoff would hold up the apply for as long as
+			// the API asks, so it is cut short.
+			delay = min(after, policy.MaxBackoff)
+		}
+		tflog.Warn(ctx, "retrying Pinecone request", map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": retry + 1,
+			"result":  describeAttempt(resp, err),
+			"delay":   delay.String(),
+		})
+
+		if resp != nil {
+			// Drain the body so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := t.wait(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *Transport) wait(ctx context.Context, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(ctx, d)
+	}
+	return sleepContext(ctx, d)
+}
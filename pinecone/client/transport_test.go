@@ -0,0 +1,327 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer answers every request with the next status in statuses, and with 200
+// once they are used up. It records the bodies it received.
+func testServer(t *testing.T, headers http.Header, statuses ...int) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		for k, v := range headers {
+			w.Header()[k] = v
+		}
+		status := http.StatusOK
+		if len(statuses) > 0 {
+			status, statuses = statuses[0], statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func testTransport(delays *[]time.Duration) *Transport {
+	return &Transport{
+		Base: http.DefaultTransport,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*delays = append(*delays, d)
+			return nil
+		},
+	}
+}
+
+func send(t *testing.T, transport *Transport, options *Options, method string, url string, body string) int {
+	t.Helper()
+
+	ctx := context.Background()
+	if options != nil {
+		ctx = withOptions(ctx, options)
+	}
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestTransport_retries(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2, MinBackoff: time.Second, MaxBackoff: 4 * time.Second}
+
+	cases := map[string]struct {
+		method   string
+		statuses []int
+		want     int
+		requests int
+	}{
+		"get succeeds":              {http.MethodGet, nil, 200, 1},
+		"get retried on 503":        {http.MethodGet, []int{503, 503}, 200, 3},
+		"get retried on 500":        {http.MethodGet, []int{500}, 200, 2},
+		"get gives up":              {http.MethodGet, []int{503, 503, 503}, 503, 3},
+		"get not retried on 404":    {http.MethodGet, []int{404}, 404, 1},
+		"delete retried on 502":     {http.MethodDelete, []int{502}, 200, 2},
+		"create retried on 429":     {http.MethodPost, []int{429}, 200, 2},
+		"create not retried on 503": {http.MethodPost, []int{503}, 503, 1},
+		"create not retried on 500": {http.MethodPost, []int{500}, 500, 1},
+		"create not retried on 504": {http.MethodPost, []int{504}, 504, 1},
+		"create not retried on 409": {http.MethodPost, []int{409}, 409, 1},
+	}
+
+	for name, c := range cases {
+		server, bodies := testServer(t, nil, c.statuses...)
+		var delays []time.Duration
+
+		body := ""
+		if c.method == http.MethodPost {
+			body = `{"name":"test"}`
+		}
+		got := send(t, testTransport(&delays), &Options{Retry: policy}, c.method, server.URL, body)
+		if got != c.want {
+			t.Errorf("%s: expected status %d, got %d", name, c.want, got)
+		}
+		if len(*bodies) != c.requests {
+			t.Errorf("%s: expected %d requests, got %d", name, c.requests, len(*bodies))
+		}
+		for i, b := range *bodies {
+			if b != body {
+				t.Errorf("%s: request %d was sent with body %q", name, i+1, b)
+			}
+		}
+		for i, d := range delays {
+			if max := policy.MinBackoff << i; d < max/2 || d > max {
+				t.Errorf("%s: retry %d waited %s, expected between %s and %s", name, i+1, d, max/2, max)
+			}
+		}
+	}
+}
+
+func TestTransport_retryAfter(t *testing.T) {
+	server, _ := testServer(t, http.Header{"Retry-After": []string{"7"}}, 429)
+	var delays []time.Duration
+
+	policy := RetryPolicy{MaxRetries: 1, MinBackoff: time.Second, MaxBackoff: 10 * time.Second}
+	send(t, testTransport(&delays), &Options{Retry: policy}, http.MethodGet, server.URL, "")
+	if len(delays) != 1 || delays[0] != 7*time.Second {
+		t.Errorf("expected to wait for the 7s requested by Retry-After, waited %v", delays)
+	}
+}
+
+func TestTransport_retryAfterClamped(t *testing.T) {
+	server, _ := testServer(t, http.Header{"Retry-After": []string{"3600"}}, 503)
+	var delays []time.Duration
+
+	policy := RetryPolicy{MaxRetries: 1, MinBackoff: time.Second, MaxBackoff: 2 * time.Second}
+	send(t, testTransport(&delays), &Options{Retry: policy}, http.MethodGet, server.URL, "")
+	if len(delays) != 1 || delays[0] != 2*time.Second {
+		t.Errorf("expected Retry-After to be clamped to the 2s MaxBackoff, waited %v", delays)
+	}
+}
+
+func TestTransport_withoutOptions(t *testing.T) {
+	server, bodies := testServer(t, nil, 503)
+	var delays []time.Duration
+
+	if got := send(t, testTransport(&delays), nil, http.MethodGet, server.URL, ""); got != 503 {
+		t.Errorf("expected status 503, got %d", got)
+	}
+	if len(*bodies) != 1 {
+		t.Errorf("expected requests made outside of a ControlPlane not to be retried, got %d requests", len(*bodies))
+	}
+}
+
+func TestInstallTransport(t *testing.T) {
+	saved := http.DefaultTransport
+	t.Cleanup(func() {
+		http.DefaultTransport = saved
+		originalTransport = saved
+		installOnce = sync.Once{}
+	})
+
+	installTransport()
+	installTransport()
+
+	wrapped, ok := http.DefaultTransport.(*Transport)
+	if !ok || wrapped.Base != originalTransport {
+		t.Fatalf("expected http.DefaultTransport to wrap the original transport once, got %#v", http.DefaultTransport)
+	}
+	if _, ok := originalTransport.(*Transport); ok {
+		t.Fatal("expected the original transport not to be wrapped again")
+	}
+
+	var got http.Header
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("User-Agent", "other-client")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || requests != 1 {
+		t.Errorf("expected other requests of the process not to be retried, got status %d after %d requests", resp.StatusCode, requests)
+	}
+	if v := got.Get("User-Agent"); v != "other-client" {
+		t.Errorf("expected other requests of the process to be sent unchanged, got user agent %q", v)
+	}
+}
+
+func TestTransport_contextCancelled(t *testing.T) {
+	server, _ := testServer(t, http.Header{"Retry-After": []string{"3600"}}, 503)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ctx = withOptions(ctx, &Options{Retry: DefaultRetryPolicy()})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+
+	start := time.Now()
+	_, err := (&http.Client{Transport: &Transport{Base: http.DefaultTransport}}).Do(req)
+	if err == nil {
+		t.Error("expected the retry to be abandoned when the context is done")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected to stop waiting with the context, waited %s", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		"missing":  {"", 0, false},
+		"seconds":  {"30", 30 * time.Second, true},
+		"negative": {"-1", 0, false},
+		"date":     {now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		"past":     {now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		"invalid":  {"soon", 0, false},
+	}
+
+	for name, c := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		if c.header != "" {
+			resp.Header.Set("Retry-After", c.header)
+		}
+		got, ok := retryAfter(resp, now)
+		if got != c.want || ok != c.ok {
+			t.Errorf("%s: expected (%s, %t), got (%s, %t)", name, c.want, c.ok, got, ok)
+		}
+	}
+}
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	cases := map[string]struct {
+		policy RetryPolicy
+		valid  bool
+	}{
+		"default":          {DefaultRetryPolicy(), true},
+		"disabled":         {RetryPolicy{MaxRetries: 0, MinBackoff: time.Second, MaxBackoff: time.Second}, true},
+		"negative retries": {RetryPolicy{MaxRetries: -1, MinBackoff: time.Second, MaxBackoff: time.Second}, false},
+		"no min backoff":   {RetryPolicy{MaxRetries: 1, MaxBackoff: time.Second}, false},
+		"max below min":    {RetryPolicy{MaxRetries: 1, MinBackoff: time.Minute, MaxBackoff: time.Second}, false},
+	}
+
+	for name, c := range cases {
+		if err := c.policy.Validate(); (err == nil) != c.valid {
+			t.Errorf("%s: expected valid %t, got error: %v", name, c.valid, err)
+		}
+	}
+}
+
+func TestTransport_headers(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	t.Cleanup(server.Close)
+
+	options := &Options{Headers: http.Header{"x-gateway-token": []string{"secret"}}, UserAgent: "terraform-provider-pinecone/test"}
+	ctx := withOptions(context.Background(), options)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	req.Header.Set("User-Agent", "go-client/v0.4.1")
+	resp, err := (&http.Client{Transport: &Transport{Base: http.DefaultTransport}}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if v := got.Get("X-Gateway-Token"); v != "secret" {
+		t.Errorf("expected the extra header to be sent, got %q", v)
+	}
+	if v := got.Get("User-Agent"); v != "terraform-provider-pinecone/test go-client/v0.4.1" {
+		t.Errorf("unexpected user agent %q", v)
+	}
+	if req.Header.Get("X-Gateway-Token") != "" {
+		t.Error("expected the original request to be left unchanged")
+	}
+}
+
+func TestTransport_proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+	}))
+	t.Cleanup(proxy.Close)
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	options := &Options{Proxy: proxyURL}
+	options.prepare()
+	if got := send(t, &Transport{Base: http.DefaultTransport}, options, http.MethodGet, "http://api.pinecone.invalid/indexes", ""); got != 200 {
+		t.Errorf("expected status 200, got %d", got)
+	}
+	if len(proxied) != 1 || proxied[0] != "http://api.pinecone.invalid/indexes" {
+		t.Errorf("expected the request to be sent through the proxy, got %v", proxied)
+	}
+}
+
+func TestTransport_rootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	options := &Options{RootCAs: pool}
+	options.prepare()
+	if got := send(t, &Transport{Base: http.DefaultTransport}, options, http.MethodGet, server.URL, ""); got != 200 {
+		t.Errorf("expected status 200, got %d", got)
+	}
+
+	ctx := withOptions(context.Background(), &Options{})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := (&http.Client{Transport: &Transport{Base: http.DefaultTransport}}).Do(req); err == nil {
+		t.Error("expected the certificate of the server not to be trusted without RootCAs")
+	}
+}
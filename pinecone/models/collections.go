@@ -44,10 +44,14 @@ type CollectionResourceModel struct {
 	Status    types.String `tfsdk:"status"`
 	Dimension types.Int64  `tfsdk:"dimension"`
 	// VectorCount types.Int64    `tfsdk:"vector_count"`
-	Environment types.String   `tfsdk:"environment"`
-	Id          types.String   `tfsdk:"id"`
-	Source      types.String   `tfsdk:"source"`
-	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+	Environment   types.String   `tfsdk:"environment"`
+	Id            types.String   `tfsdk:"id"`
+	Source        types.String   `tfsdk:"source"`
+	AdoptExisting types.Bool     `tfsdk:"adopt_existing"`
+	WaitForReady  types.Bool     `tfsdk:"wait_for_ready"`
+	ProjectApiKey types.String   `tfsdk:"project_api_key"`
+	Profile       types.String   `tfsdk:"profile"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (model *CollectionResourceModel) Read(collection *pinecone.Collection) {
@@ -77,8 +81,10 @@ type CollectionDataSourceModel struct {
 	Status    types.String `tfsdk:"status"`
 	Dimension types.Int64  `tfsdk:"dimension"`
 	// VectorCount types.Int64  `tfsdk:"vector_count"`
-	Environment types.String `tfsdk:"environment"`
-	Id          types.String `tfsdk:"id"`
+	Environment   types.String `tfsdk:"environment"`
+	Id            types.String `tfsdk:"id"`
+	ProjectApiKey types.String `tfsdk:"project_api_key"`
+	Profile       types.String `tfsdk:"profile"`
 }
 
 func (model *CollectionDataSourceModel) Read(collection *pinecone.Collection) {
@@ -98,5 +104,15 @@ func (model *CollectionDataSourceModel) Read(collection *pinecone.Collection) {
 // CollectionsDataSourceModel describes the data source data model.
 type CollectionsDataSourceModel struct {
 	Collections []CollectionModel `tfsdk:"collections"`
+	Names       types.List        `tfsdk:"names"`
+	NameRegex   types.String      `tfsdk:"name_regex"`
+	Status      types.String      `tfsdk:"status"`
+	Environment types.String      `tfsdk:"environment"`
+	Dimension   types.Int64       `tfsdk:"dimension"`
+	MinSize     types.Int64       `tfsdk:"min_size"`
+	MaxSize     types.Int64       `tfsdk:"max_size"`
 	Id          types.String      `tfsdk:"id"`
+
+	ProjectApiKey types.String `tfsdk:"project_api_key"`
+	Profile       types.String `tfsdk:"profile"`
 }
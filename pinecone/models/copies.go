@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// IndexCopyResourceModel describes the resource data model.
+type IndexCopyResourceModel struct {
+	Id                types.String   `tfsdk:"id"`
+	SourceIndex       types.String   `tfsdk:"source_index"`
+	TargetIndex       types.String   `tfsdk:"target_index"`
+	Namespaces        types.List     `tfsdk:"namespaces"`
+	ExcludeNamespaces types.List     `tfsdk:"exclude_namespaces"`
+	IdPrefix          types.String   `tfsdk:"id_prefix"`
+	MetadataFilter    types.String   `tfsdk:"metadata_filter"`
+	BatchSize         types.Int64    `tfsdk:"batch_size"`
+	Concurrency       types.Int64    `tfsdk:"concurrency"`
+	Complete          types.Bool     `tfsdk:"complete"`
+	Checkpoint        types.Object   `tfsdk:"checkpoint"`
+	SourceVectorCount types.Int64    `tfsdk:"source_vector_count"`
+	CopiedVectorCount types.Int64    `tfsdk:"copied_vector_count"`
+	TargetVectorCount types.Int64    `tfsdk:"target_vector_count"`
+	ProjectApiKey     types.String   `tfsdk:"project_api_key"`
+	Profile           types.String   `tfsdk:"profile"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// IndexCopyCheckpointModel records where an interrupted copy resumes: the namespaces
+// before Namespace have been copied, and Namespace continues from PaginationToken.
+type IndexCopyCheckpointModel struct {
+	Namespace       types.String `tfsdk:"namespace"`
+	PaginationToken types.String `tfsdk:"pagination_token"`
+}
+
+func (model IndexCopyCheckpointModel) AttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"namespace":        types.StringType,
+		"pagination_token": types.StringType,
+	}
+}
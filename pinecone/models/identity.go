@@ -0,0 +1,22 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package models
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// CallerIdentityDataSourceModel describes the caller identity data source data model.
+type CallerIdentityDataSourceModel struct {
+	Id            types.String `tfsdk:"id"`
+	ProjectId     types.String `tfsdk:"project_id"`
+	ProjectApiKey types.String `tfsdk:"project_api_key"`
+	Profile       types.String `tfsdk:"profile"`
+}
+
+// ResourceIdentityModel describes the identity of an index or a collection.
+type ResourceIdentityModel struct {
+	ProjectId types.String `tfsdk:"project_id"`
+	Name      types.String `tfsdk:"name"`
+}
@@ -23,9 +23,9 @@ type IndexModel struct {
 	Status    types.Object `tfsdk:"status"`
 }
 
+// Read sets the model from an index. The resource and data source models share
+// these attributes and are read through it as well, so the three stay in sync.
 func (model *IndexModel) Read(ctx context.Context, index *pinecone.Index) diag.Diagnostics {
-	var diags diag.Diagnostics
-
 	model.Name = types.StringValue(index.Name)
 	model.Dimension = types.Int64Value(int64(index.Dimension))
 	model.Metric = types.StringValue(string(index.Metric))
@@ -45,105 +45,87 @@ func (model *IndexModel) Read(ctx context.Context, index *pinecone.Index) diag.D
 		return diags
 	}
 
-	model.Status, diags = types.ObjectValueFrom(ctx, IndexStatusModel{}.AttrTypes(), IndexStatusModel{
-		Ready: types.BoolValue(index.Status.Ready),
-		State: types.StringValue(string(index.Status.State)),
-	})
-	if diags.HasError() {
-		return diags
+	var status IndexStatusModel
+	if index.Status != nil {
+		status = IndexStatusModel{
+			Ready: types.BoolValue(index.Status.Ready),
+			State: types.StringValue(string(index.Status.State)),
+		}
+	} else {
+		status = IndexStatusModel{
+			Ready: types.BoolValue(false),
+			State: types.StringNull(),
+		}
 	}
-
+	model.Status, diags = types.ObjectValueFrom(ctx, IndexStatusModel{}.AttrTypes(), status)
 	return diags
 }
 
 // IndexResourceModel defined the Index model for the resource.
 type IndexResourceModel struct {
-	Id        types.String   `tfsdk:"id"`
-	Name      types.String   `tfsdk:"name"`
-	Dimension types.Int64    `tfsdk:"dimension"`
-	Metric    types.String   `tfsdk:"metric"`
-	Host      types.String   `tfsdk:"host"`
-	Spec      types.Object   `tfsdk:"spec"`
-	Status    types.Object   `tfsdk:"status"`
-	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+	Id            types.String   `tfsdk:"id"`
+	Name          types.String   `tfsdk:"name"`
+	Dimension     types.Int64    `tfsdk:"dimension"`
+	Metric        types.String   `tfsdk:"metric"`
+	Host          types.String   `tfsdk:"host"`
+	Spec          types.Object   `tfsdk:"spec"`
+	Status        types.Object   `tfsdk:"status"`
+	AdoptExisting types.Bool     `tfsdk:"adopt_existing"`
+	WaitForReady  types.Bool     `tfsdk:"wait_for_ready"`
+	ForceDestroy  types.Bool     `tfsdk:"force_destroy"`
+	FinalSnapshot types.Object   `tfsdk:"final_snapshot"`
+	ProjectApiKey types.String   `tfsdk:"project_api_key"`
+	Profile       types.String   `tfsdk:"profile"`
+	Timeouts      timeouts.Value `tfsdk:"timeouts"`
+
+	EstimatedHourlyCost  types.Float64 `tfsdk:"estimated_hourly_cost"`
+	EstimatedMonthlyCost types.Float64 `tfsdk:"estimated_monthly_cost"`
 }
 
 func (model *IndexResourceModel) Read(ctx context.Context, index *pinecone.Index) diag.Diagnostics {
-	var diags diag.Diagnostics
-
-	model.Id = types.StringValue(index.Name)
-	model.Name = types.StringValue(index.Name)
-	model.Dimension = types.Int64Value(int64(index.Dimension))
-	model.Metric = types.StringValue(string(index.Metric))
-	model.Host = types.StringValue(index.Host)
-
-	pod, diags := NewIndexPodSpecModel(ctx, index.Spec.Pod)
-	if diags.HasError() {
-		return diags
-	}
-	spec := IndexSpecModel{
-		Pod:        pod,
-		Serverless: NewIndexServerlessSpecModel(index.Spec.Serverless),
-	}
-
-	model.Spec, diags = types.ObjectValueFrom(ctx, IndexSpecModel{}.AttrTypes(), spec)
-	if diags.HasError() {
-		return diags
-	}
-
-	model.Status, diags = types.ObjectValueFrom(ctx, IndexStatusModel{}.AttrTypes(), IndexStatusModel{
-		Ready: types.BoolValue(index.Status.Ready),
-		State: types.StringValue(string(index.Status.State)),
-	})
+	var common IndexModel
+	diags := common.Read(ctx, index)
 	if diags.HasError() {
 		return diags
 	}
 
+	model.Id = types.StringValue(index.Name)
+	model.Name = common.Name
+	model.Dimension = common.Dimension
+	model.Metric = common.Metric
+	model.Host = common.Host
+	model.Spec = common.Spec
+	model.Status = common.Status
 	return diags
 }
 
-// IndexDatasourceeModel defined the Index model for the datasource.
+// IndexDatasourceModel defined the Index model for the datasource.
 type IndexDatasourceModel struct {
-	Id        types.String `tfsdk:"id"`
-	Name      types.String `tfsdk:"name"`
-	Dimension types.Int64  `tfsdk:"dimension"`
-	Metric    types.String `tfsdk:"metric"`
-	Host      types.String `tfsdk:"host"`
-	Spec      types.Object `tfsdk:"spec"`
-	Status    types.Object `tfsdk:"status"`
+	Id            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Dimension     types.Int64  `tfsdk:"dimension"`
+	Metric        types.String `tfsdk:"metric"`
+	Host          types.String `tfsdk:"host"`
+	Spec          types.Object `tfsdk:"spec"`
+	Status        types.Object `tfsdk:"status"`
+	ProjectApiKey types.String `tfsdk:"project_api_key"`
+	Profile       types.String `tfsdk:"profile"`
 }
 
 func (model *IndexDatasourceModel) Read(ctx context.Context, index *pinecone.Index) diag.Diagnostics {
-	var diags diag.Diagnostics
-
-	model.Id = types.StringValue(index.Name)
-	model.Name = types.StringValue(index.Name)
-	model.Dimension = types.Int64Value(int64(index.Dimension))
-	model.Metric = types.StringValue(string(index.Metric))
-	model.Host = types.StringValue(index.Host)
-
-	pod, diags := NewIndexPodSpecModel(ctx, index.Spec.Pod)
-	if diags.HasError() {
-		return diags
-	}
-	spec := IndexSpecModel{
-		Pod:        pod,
-		Serverless: NewIndexServerlessSpecModel(index.Spec.Serverless),
-	}
-
-	model.Spec, diags = types.ObjectValueFrom(ctx, IndexSpecModel{}.AttrTypes(), spec)
-	if diags.HasError() {
-		return diags
-	}
-
-	model.Status, diags = types.ObjectValueFrom(ctx, IndexStatusModel{}.AttrTypes(), IndexStatusModel{
-		Ready: types.BoolValue(index.Status.Ready),
-		State: types.StringValue(string(index.Status.State)),
-	})
+	var common IndexModel
+	diags := common.Read(ctx, index)
 	if diags.HasError() {
 		return diags
 	}
 
+	model.Id = types.StringValue(index.Name)
+	model.Name = common.Name
+	model.Dimension = common.Dimension
+	model.Metric = common.Metric
+	model.Host = common.Host
+	model.Spec = common.Spec
+	model.Status = common.Status
 	return diags
 }
 
@@ -259,7 +241,7 @@ type IndexServerlessSpecModel struct {
 func NewIndexServerlessSpec(spec *IndexServerlessSpecModel) *pinecone.ServerlessSpec {
 	if spec != nil {
 		return &pinecone.ServerlessSpec{
-			Cloud:  pinecone.Cloud(spec.Cloud.String()),
+			Cloud:  pinecone.Cloud(spec.Cloud.ValueString()),
 			Region: spec.Region.ValueString(),
 		}
 	}
@@ -283,6 +265,18 @@ func (model IndexServerlessSpecModel) AttrTypes() map[string]attr.Type {
 	}
 }
 
+type IndexFinalSnapshotModel struct {
+	Name   types.String `tfsdk:"name"`
+	Retain types.Bool   `tfsdk:"retain"`
+}
+
+func (model IndexFinalSnapshotModel) AttrTypes() map[string]attr.Type {
+	return map[string]attr.Type{
+		"name":   types.StringType,
+		"retain": types.BoolType,
+	}
+}
+
 type IndexStatusModel struct {
 	Ready types.Bool   `tfsdk:"ready"`
 	State types.String `tfsdk:"state"`
@@ -296,6 +290,16 @@ func (status IndexStatusModel) AttrTypes() map[string]attr.Type {
 }
 
 type IndexesDataSourceModel struct {
-	Indexes []IndexModel `tfsdk:"indexes"`
-	Id      types.String `tfsdk:"id"`
+	Indexes    []IndexModel `tfsdk:"indexes"`
+	Names      types.List   `tfsdk:"names"`
+	NameRegex  types.String `tfsdk:"name_regex"`
+	NamePrefix types.String `tfsdk:"name_prefix"`
+	SpecType   types.String `tfsdk:"spec_type"`
+	Cloud      types.String `tfsdk:"cloud"`
+	Region     types.String `tfsdk:"region"`
+	ReadyOnly  types.Bool   `tfsdk:"ready_only"`
+	Id         types.String `tfsdk:"id"`
+
+	ProjectApiKey types.String `tfsdk:"project_api_key"`
+	Profile       types.String `tfsdk:"profile"`
 }
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package models
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+// IndexMigrationResourceModel describes the resource data model.
+type IndexMigrationResourceModel struct {
+	Id                types.String   `tfsdk:"id"`
+	SourceIndex       types.String   `tfsdk:"source_index"`
+	Collection        types.String   `tfsdk:"collection"`
+	KeepCollection    types.Bool     `tfsdk:"keep_collection"`
+	TargetName        types.String   `tfsdk:"target_name"`
+	TargetCloud       types.String   `tfsdk:"target_cloud"`
+	TargetRegion      types.String   `tfsdk:"target_region"`
+	TargetHost        types.String   `tfsdk:"target_host"`
+	TargetStatus      types.Object   `tfsdk:"target_status"`
+	SourceVectorCount types.Int64    `tfsdk:"source_vector_count"`
+	TargetVectorCount types.Int64    `tfsdk:"target_vector_count"`
+	ProjectApiKey     types.String   `tfsdk:"project_api_key"`
+	Profile           types.String   `tfsdk:"profile"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+// ReadTarget reads the target index of the migration into the model.
+func (model *IndexMigrationResourceModel) ReadTarget(ctx context.Context, index *pinecone.Index) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.Id = types.StringValue(index.Name)
+	model.TargetName = types.StringValue(index.Name)
+	model.TargetHost = types.StringValue(index.Host)
+	if index.Spec != nil && index.Spec.Serverless != nil {
+		model.TargetCloud = types.StringValue(string(index.Spec.Serverless.Cloud))
+		model.TargetRegion = types.StringValue(index.Spec.Serverless.Region)
+	}
+
+	status := IndexStatusModel{
+		Ready: types.BoolValue(false),
+		State: types.StringNull(),
+	}
+	if index.Status != nil {
+		status = IndexStatusModel{
+			Ready: types.BoolValue(index.Status.Ready),
+			State: types.StringValue(string(index.Status.State)),
+		}
+	}
+	model.TargetStatus, diags = types.ObjectValueFrom(ctx, IndexStatusModel{}.AttrTypes(), status)
+	return diags
+}
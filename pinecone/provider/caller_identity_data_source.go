@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &CallerIdentityDataSource{}
+
+func NewCallerIdentityDataSource() datasource.DataSource {
+	return &CallerIdentityDataSource{PineconeDatasource: &PineconeDatasource{}}
+}
+
+// CallerIdentityDataSource defines the data source implementation.
+type CallerIdentityDataSource struct {
+	*PineconeDatasource
+}
+
+func (d *CallerIdentityDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_caller_identity"
+}
+
+func (d *CallerIdentityDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Validates the API key the provider is configured with and describes the project it belongs to. " +
+			"The Pinecone API does not expose the project name, and the project ID is taken from the host of one of its indexes.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Caller identity identifier, the project ID when it is known.",
+				Computed:            true,
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project the API key belongs to, or null when the project has no index to take it from.",
+				Computed:            true,
+			},
+		},
+	}
+	addAttributes(resp.Schema.Attributes, credentialDataSourceAttributes())
+}
+
+func (d *CallerIdentityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.CallerIdentityDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := d.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identity, err := resolveCallerIdentity(ctx, api)
+	if err != nil {
+		resp.Diagnostics.Append(credentialsError(err))
+		return
+	}
+
+	if identity.ProjectID != "" {
+		data.Id = types.StringValue(identity.ProjectID)
+		data.ProjectId = types.StringValue(identity.ProjectID)
+	} else {
+		data.Id = types.StringValue("unknown")
+		data.ProjectId = types.StringNull()
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+func TestCallerIdentityDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	projects := map[string]*client.Memory{"prod-key": client.NewMemory()}
+	projects["prod-key"].PutIndex(&pinecone.Index{Name: "docs", Host: "docs-4zo0ijk.svc.us-east1-aws.pinecone.io"})
+	data := newTestProviderData(client.NewMemory())
+	data.Clients = newTestClientCache(projects)
+	d := NewCallerIdentityDataSource().(*CallerIdentityDataSource)
+	s := configureTestDataSource(t, d, data)
+
+	cases := map[string]struct {
+		apiKey types.String
+		want   types.String
+	}{
+		"provider project": {types.StringNull(), types.StringNull()},
+		"other project":    {types.StringValue("prod-key"), types.StringValue("4zo0ijk")},
+	}
+
+	for name, c := range cases {
+		config := models.CallerIdentityDataSourceModel{Id: types.StringNull(), ProjectId: types.StringNull(), ProjectApiKey: c.apiKey, Profile: types.StringNull()}
+		resp := &datasource.ReadResponse{State: newTestDataSourceState(s)}
+		d.Read(ctx, datasource.ReadRequest{Config: newTestConfig(t, s, &config)}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: read failed: %v", name, resp.Diagnostics)
+		}
+
+		var state models.CallerIdentityDataSourceModel
+		if diags := resp.State.Get(ctx, &state); diags.HasError() {
+			t.Fatalf("%s: invalid state: %v", name, diags)
+		}
+		if !state.ProjectId.Equal(c.want) || state.Id.IsNull() {
+			t.Errorf("%s: expected project %s, got %+v", name, c.want, state)
+		}
+	}
+}
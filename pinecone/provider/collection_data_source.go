@@ -64,6 +64,7 @@ func (d *CollectionDataSource) Schema(ctx context.Context, req datasource.Schema
 			},
 		},
 	}
+	addAttributes(resp.Schema.Attributes, credentialDataSourceAttributes())
 }
 
 func (d *CollectionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -76,7 +77,13 @@ func (d *CollectionDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
-	collection, err := d.client.DescribeCollection(ctx, data.Name.ValueString())
+	api, diags := d.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collection, err := api.DescribeCollection(ctx, data.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to describe collection, got error: %s", err))
 		return
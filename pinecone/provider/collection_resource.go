@@ -14,19 +14,29 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
 	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
 )
 
 const (
 	defaultCollectionCreateTimeout time.Duration = 10 * time.Minute
+	defaultCollectionReadTimeout   time.Duration = 5 * time.Minute
+	defaultCollectionUpdateTimeout time.Duration = 5 * time.Minute
 	defaultCollectionDeleteTimeout time.Duration = 10 * time.Minute
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &CollectionResource{}
 var _ resource.ResourceWithImportState = &CollectionResource{}
+var _ resource.ResourceWithIdentity = &CollectionResource{}
+var _ resource.ResourceWithModifyPlan = &CollectionResource{}
 
 func NewCollectionResource() resource.Resource {
 	return &CollectionResource{PineconeResource: &PineconeResource{}}
@@ -39,6 +49,12 @@ type CollectionResource struct {
 
 func (r *CollectionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_collection"
+	// The identity is updated when the project resolved for it changes.
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+func (r *CollectionResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("collection")
 }
 
 func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -52,10 +68,16 @@ func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequ
 			"name": schema.StringAttribute{
 				MarkdownDescription: "The name of the collection.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"source": schema.StringAttribute{
 				MarkdownDescription: "The name of the source index to be used as the source for the collection.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"size": schema.Int64Attribute{
 				MarkdownDescription: "The size of the collection in bytes.",
@@ -78,67 +100,100 @@ func (r *CollectionResource) Schema(ctx context.Context, req resource.SchemaRequ
 				MarkdownDescription: "The environment where the collection is hosted.",
 				Computed:            true,
 			},
+			"adopt_existing": schema.BoolAttribute{
+				MarkdownDescription: "Whether to adopt an existing collection with the same name instead of failing to create it. " +
+					"The existing collection is only adopted when its dimension and environment match the source index. Defaults to false.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether to wait for the collection to become ready after it is created. Defaults to true.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
 		},
 		Blocks: map[string]schema.Block{
 			"timeouts": timeouts.Block(ctx,
 				timeouts.Opts{
-					Create: true,
-					CreateDescription: `Timeout defaults to 5 mins. Accepts a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
-						`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
-						`"s" (seconds), "m" (minutes), "h" (hours).`,
-					Delete: true,
-					DeleteDescription: `Timeout defaults to 5 mins. Accepts a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
-						`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
-						`"s" (seconds), "m" (minutes), "h" (hours).`,
+					Create:            true,
+					CreateDescription: timeoutDescription(defaultCollectionCreateTimeout),
+					Read:              true,
+					ReadDescription:   timeoutDescription(defaultCollectionReadTimeout),
+					Update:            true,
+					UpdateDescription: timeoutDescription(defaultCollectionUpdateTimeout),
+					Delete:            true,
+					DeleteDescription: timeoutDescription(defaultCollectionDeleteTimeout),
 				},
 			),
 		},
 	}
+	addAttributes(resp.Schema.Attributes, credentialResourceAttributes("collection", r.PineconeResource))
+}
+
+func (r *CollectionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.denyReadOnlyPlan(req, resp, "collection")
 }
 
 func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("create", "collection")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data models.CollectionResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	payload := pinecone.CreateCollectionRequest{
 		Name:   data.Name.ValueString(),
 		Source: data.Source.ValueString(),
 	}
 
-	_, err := r.client.CreateCollection(ctx, &payload)
+	_, err := api.CreateCollection(ctx, &payload)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to create collection", err.Error())
-		return
+		if err = r.recoverCreateConflict(ctx, api, err, &data); err != nil {
+			resp.Diagnostics.AddError("Failed to create collection", err.Error())
+			return
+		}
 	}
 
 	// Wait for collection to be ready
 	// Create() is passed a default timeout to use if no value
 	// has been supplied in the Terraform configuration.
-	createTimeout, diags := data.Timeouts.Create(ctx, defaultCollectionCreateTimeout)
+	createTimeout, diags := data.Timeouts.Create(ctx, timeoutOrDefault(r.timeouts.Create, defaultCollectionCreateTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err = retry.RetryContext(ctx, createTimeout, func() *retry.RetryError {
-		collection, err := r.client.DescribeCollection(ctx, data.Name.ValueString())
+	waitForReady := data.WaitForReady.ValueBool()
+	w := r.newWaiter("collection", data.Name.ValueString(), createTimeout, func(ctx context.Context) (string, waiter.Class, error) {
+		collection, err := api.DescribeCollection(ctx, data.Name.ValueString())
+		if err != nil {
+			return "", waiter.Pending, err
+		}
 
 		data.Read(collection)
 		// Save current status to state
 		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 
-		if err != nil {
-			return retry.NonRetryableError(err)
+		class := classifyCollectionCreateState(collection.Status)
+		if !waitForReady && class == waiter.Pending {
+			class = waiter.Target
 		}
-		if collection.Status != "Ready" {
-			return retry.RetryableError(fmt.Errorf("collection not ready. State: %s", collection.Status))
-		}
-		return nil
+		return string(collection.Status), class, nil
 	})
-	if err != nil {
+	if _, err := w.Wait(ctx); err != nil {
 		resp.Diagnostics.AddError("Failed to wait for collection to become ready.", err.Error())
 		return
 	}
@@ -147,6 +202,8 @@ func (r *CollectionResource) Create(ctx context.Context, req resource.CreateRequ
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	// Collections have no host, so the project is that of the API key.
+	resp.Diagnostics.Append(setResourceIdentity(ctx, resp.Identity, r.clients, api, data.Name.ValueString(), "")...)
 }
 
 func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -156,61 +213,219 @@ func (r *CollectionResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	collection, err := r.client.DescribeCollection(ctx, data.Id.ValueString())
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, timeoutOrDefault(r.timeouts.Read, defaultCollectionReadTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	collection, err := api.DescribeCollection(ctx, data.Id.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to describe collection", err.Error())
+		if isNotFoundError(err) {
+			resp.State.RemoveResource(ctx)
+		} else {
+			resp.Diagnostics.AddError("Failed to describe collection", err.Error())
+		}
 		return
 	}
 
 	data.Read(collection)
+	if data.WaitForReady.IsNull() {
+		data.WaitForReady = types.BoolValue(true)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setResourceIdentity(ctx, resp.Identity, r.clients, api, data.Name.ValueString(), "")...)
 }
 
 func (r *CollectionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Collections currently do not support updates
-	resp.Diagnostics.Append(diag.NewErrorDiagnostic("not supported", "This resource's Update method should not have been called"))
+	resp.Diagnostics.Append(r.denyReadOnly("update", "collection")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data models.CollectionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, timeoutOrDefault(r.timeouts.Update, defaultCollectionUpdateTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	// Collections themselves cannot be updated, only provider settings such as
+	// wait_for_ready and timeouts. Refresh the computed attributes.
+	collection, err := api.DescribeCollection(ctx, data.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to describe collection", err.Error())
+		return
+	}
+
+	data.Read(collection)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *CollectionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("delete", "collection")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data models.CollectionResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := r.client.DeleteCollection(ctx, data.Name.ValueString())
-	if err != nil {
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A delete that was retried after a server error can find the collection already gone.
+	err := api.DeleteCollection(ctx, data.Name.ValueString())
+	if err != nil && !isNotFoundError(err) {
 		resp.Diagnostics.AddError("Failed to delete collection", err.Error())
 		return
 	}
 	// Wait for collection to be deleted
-	// Create() is passed a default timeout to use if no value
+	// Delete() is passed a default timeout to use if no value
 	// has been supplied in the Terraform configuration.
-	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultIndexDeleteTimeout)
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, timeoutOrDefault(r.timeouts.Delete, defaultCollectionDeleteTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err = retry.RetryContext(ctx, deleteTimeout, func() *retry.RetryError {
-		collection, err := r.client.DescribeCollection(ctx, data.Id.ValueString())
-		// tflog.Info(ctx, fmt.Sprintf("Deleting Collection. Status: '%s'", collection.Status))
-
+	w := r.newWaiter("collection", data.Name.ValueString(), deleteTimeout, func(ctx context.Context) (string, waiter.Class, error) {
+		collection, err := api.DescribeCollection(ctx, data.Id.ValueString())
 		if err != nil {
-			if strings.Contains(err.Error(), "404") {
-				return nil
+			if isNotFoundError(err) {
+				return deletedState, waiter.Target, nil
 			}
-			return retry.NonRetryableError(err)
+			return "", waiter.Pending, err
 		}
-		return retry.RetryableError(fmt.Errorf("collection not deleted. State: %s", collection.Status))
+		return string(collection.Status), waiter.Pending, nil
 	})
-	if err != nil {
+	if _, err := w.Wait(ctx); err != nil {
 		resp.Diagnostics.AddError("Failed to wait for collection to be deleted.", err.Error())
 		return
 	}
 }
 
+// ImportState accepts the name of the collection, a project_id/name pair or its
+// identity, and checks that the collection exists in the project of the provider.
 func (r *CollectionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var id importID
+	if req.ID == "" && req.Identity != nil {
+		var diags diag.Diagnostics
+		id, diags = importIDFromIdentity(ctx, req.Identity)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		var err error
+		if id, err = parseImportID(req.ID, false); err != nil {
+			resp.Diagnostics.AddError("Invalid import ID",
+				fmt.Sprintf("Import a collection by its name or a project_id/name pair: %s.", err))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, types.StringNull(), types.StringNull())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeoutOrDefault(r.timeouts.Read, defaultCollectionReadTimeout))
+	defer cancel()
+
+	keyProjectID, diags := checkImportProject(ctx, r.clients, api, "collection", id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collection, err := api.DescribeCollection(ctx, id.Name)
+	if err != nil {
+		if isNotFoundError(err) {
+			resp.Diagnostics.AddError("Collection not found",
+				fmt.Sprintf("Collection %s does not exist in the project of the provider's API key, so it cannot be imported.", id.Name))
+		} else {
+			resp.Diagnostics.AddError("Failed to describe collection", err.Error())
+		}
+		return
+	}
+	// Collections have no host, so the project is that of the API key.
+	resp.Diagnostics.Append(verifyImportProject("collection", id, "", keyProjectID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), collection.Name)...)
+	resp.Diagnostics.Append(setResourceIdentity(ctx, resp.Identity, r.clients, api, collection.Name, keyProjectID)...)
+}
+
+// recoverCreateConflict returns nil when adopt_existing is set and the existing collection
+// with the planned name was taken from an index like the planned source, otherwise the
+// error to report.
+func (r *CollectionResource) recoverCreateConflict(ctx context.Context, api client.ControlPlane, createErr error, data *models.CollectionResourceModel) error {
+	if !data.AdoptExisting.ValueBool() || !isAlreadyExistsError(createErr) {
+		return createErr
+	}
+
+	collection, err := api.DescribeCollection(ctx, data.Name.ValueString())
+	if err != nil {
+		return createErr
+	}
+	if classifyCollectionCreateState(collection.Status) == waiter.Failed {
+		return fmt.Errorf("%w; the existing collection cannot be adopted because it is %s", createErr, collection.Status)
+	}
+
+	// The API does not record which index a collection was taken from, so compare
+	// against the source index instead.
+	source, err := api.DescribeIndex(ctx, data.Source.ValueString())
+	if err != nil {
+		return fmt.Errorf("%w; the existing collection cannot be adopted because the source index could not be described: %s", createErr, err)
+	}
+
+	var mismatches []string
+	if collection.Dimension != nil && *collection.Dimension != source.Dimension {
+		mismatches = append(mismatches, fmt.Sprintf("dimension is %d, expected %d", *collection.Dimension, source.Dimension))
+	}
+	if source.Spec != nil && source.Spec.Pod != nil && collection.Environment != source.Spec.Pod.Environment {
+		mismatches = append(mismatches, fmt.Sprintf("environment is %s, expected %s", collection.Environment, source.Spec.Pod.Environment))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w; the existing collection cannot be adopted because its %s", createErr, strings.Join(mismatches, ", "))
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("adopting existing collection %s", collection.Name), map[string]interface{}{
+		"status": string(collection.Status),
+	})
+	return nil
 }
@@ -4,10 +4,18 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"testing"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
-	"testing"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
 func TestAccCollectionResource(t *testing.T) {
@@ -67,3 +75,140 @@ resource "pinecone_collection" "test" {
 }
 `, name, name)
 }
+
+// newTestCollectionResource returns a collection resource backed by memory, holding a
+// ready pod-based index named "source".
+func newTestCollectionResource(t *testing.T, memory *client.Memory) *CollectionResource {
+	t.Helper()
+
+	memory.PutIndex(&pinecone.Index{
+		Name:      "source",
+		Dimension: 8,
+		Metric:    pinecone.Cosine,
+		Host:      "source-abc123.svc.us-west4-gcp.pinecone.io",
+		Spec:      &pinecone.IndexSpec{Pod: &pinecone.PodSpec{Environment: "us-west4-gcp", PodType: "s1.x1", PodCount: 1, Replicas: 1, ShardCount: 1}},
+		Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	})
+	memory.PutVectors("source-abc123.svc.us-west4-gcp.pinecone.io", "", &pinecone.Vector{Id: "a", Values: make([]float32, 8)})
+
+	return newTestResource[*CollectionResource](t, NewCollectionResource, newTestProviderData(memory))
+}
+
+// testCollectionPlan returns the plan of a collection of the "source" index.
+func testCollectionPlan(name string) models.CollectionResourceModel {
+	return models.CollectionResourceModel{
+		Id:            types.StringUnknown(),
+		Name:          types.StringValue(name),
+		Source:        types.StringValue("source"),
+		Size:          types.Int64Unknown(),
+		Status:        types.StringUnknown(),
+		Dimension:     types.Int64Unknown(),
+		Environment:   types.StringUnknown(),
+		AdoptExisting: types.BoolValue(false),
+		WaitForReady:  types.BoolValue(true),
+		Timeouts:      nullTimeouts(),
+	}
+}
+
+func TestCollectionResource_lifecycle(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	memory.ReadyAfter = 3
+	r := newTestCollectionResource(t, memory)
+
+	resp := createTestResource(t, r, testCollectionPlan("backup"))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", resp.Diagnostics)
+	}
+	var state models.CollectionResourceModel
+	if diags := resp.State.Get(ctx, &state); diags.HasError() {
+		t.Fatalf("invalid state: %v", diags)
+	}
+	if state.Status.ValueString() != string(pinecone.CollectionStatusReady) || state.Dimension.ValueInt64() != 8 || state.Environment.ValueString() != "us-west4-gcp" {
+		t.Errorf("unexpected state after create: %+v", state)
+	}
+
+	readResp := &fwresource.ReadResponse{State: resp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, readResp)
+	if readResp.Diagnostics.HasError() || readResp.State.Raw.IsNull() {
+		t.Fatalf("read failed: %v", readResp.Diagnostics)
+	}
+
+	deleteResp := &fwresource.DeleteResponse{State: resp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: resp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("delete failed: %v", deleteResp.Diagnostics)
+	}
+	if _, err := memory.DescribeCollection(ctx, "backup"); !isNotFoundError(err) {
+		t.Errorf("expected the collection to be deleted, got: %v", err)
+	}
+}
+
+func TestCollectionResource_Read_drift(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	r := newTestCollectionResource(t, memory)
+
+	resp := createTestResource(t, r, testCollectionPlan("drift"))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", resp.Diagnostics)
+	}
+
+	if err := memory.DeleteCollection(ctx, "drift"); err != nil {
+		t.Fatal(err)
+	}
+	readResp := &fwresource.ReadResponse{State: resp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("read failed: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.IsNull() {
+		t.Error("expected a collection deleted outside of Terraform to be removed from state")
+	}
+}
+
+func TestCollectionResource_Create_errors(t *testing.T) {
+	cases := map[string]struct {
+		hook    func(memory *client.Memory) func(op client.Operation, name string) error
+		summary string
+	}{
+		"create fails": {
+			hook: func(memory *client.Memory) func(op client.Operation, name string) error {
+				return func(op client.Operation, name string) error {
+					if op == client.OpCreateCollection {
+						return errors.New("failed to create collection: Quota exceeded")
+					}
+					return nil
+				}
+			},
+			summary: "Failed to create collection",
+		},
+		"terminated while creating": {
+			hook: func(memory *client.Memory) func(op client.Operation, name string) error {
+				return func(op client.Operation, name string) error {
+					if op == client.OpDescribeCollection {
+						memory.PutCollection(&pinecone.Collection{Name: name, Status: pinecone.CollectionStatusTerminating})
+					}
+					return nil
+				}
+			},
+			summary: "Failed to wait for collection to become ready.",
+		},
+	}
+
+	for name, c := range cases {
+		memory := client.NewMemory()
+		memory.ReadyAfter = 100
+		r := newTestCollectionResource(t, memory)
+		memory.Hook = c.hook(memory)
+
+		resp := createTestResource(t, r, testCollectionPlan("failing"))
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if got := resp.Diagnostics.Errors()[0].Summary(); got != c.summary {
+			t.Errorf("%s: expected error %q, got %q", name, c.summary, got)
+		}
+	}
+}
@@ -6,12 +6,14 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"time"
+	"regexp"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/go-pinecone/pinecone"
 	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
@@ -69,12 +71,42 @@ func (d *CollectionsDataSource) Schema(ctx context.Context, req datasource.Schem
 					},
 				},
 			},
+			"names": schema.ListAttribute{
+				MarkdownDescription: "Names of the collections matching the filters, sorted alphabetically.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only return collections whose name matches this [regular expression](https://pkg.go.dev/regexp/syntax).",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only return collections with this status, e.g. 'Ready'.",
+				Optional:            true,
+			},
+			"environment": schema.StringAttribute{
+				MarkdownDescription: "Only return collections hosted in this environment.",
+				Optional:            true,
+			},
+			"dimension": schema.Int64Attribute{
+				MarkdownDescription: "Only return collections of vectors with this dimension.",
+				Optional:            true,
+			},
+			"min_size": schema.Int64Attribute{
+				MarkdownDescription: "Only return collections of at least this size in bytes.",
+				Optional:            true,
+			},
+			"max_size": schema.Int64Attribute{
+				MarkdownDescription: "Only return collections of at most this size in bytes.",
+				Optional:            true,
+			},
 			"id": schema.StringAttribute{
-				MarkdownDescription: "Collections identifier",
+				MarkdownDescription: "Collections identifier, derived from the names of the matching collections.",
 				Computed:            true,
 			},
 		},
 	}
+	addAttributes(resp.Schema.Attributes, credentialDataSourceAttributes())
 }
 
 func (d *CollectionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -87,17 +119,94 @@ func (d *CollectionsDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
-	collections, err := d.client.ListCollections(ctx)
+	filter, err := newCollectionFilter(&data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+		return
+	}
+
+	api, diags := d.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collections, err := api.ListCollections(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ListCollections, got error: %s", err))
 		return
 	}
 
+	sort.Slice(collections, func(i, j int) bool {
+		return collections[i].Name < collections[j].Name
+	})
+
+	data.Collections = []models.CollectionModel{}
+	names := []string{}
 	for _, c := range collections {
+		if !filter.Match(c) {
+			continue
+		}
 		data.Collections = append(data.Collections, *models.NewCollectionModel(c))
+		names = append(names, c.Name)
 	}
 
+	data.Names, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
+
 	// Save data into Terraform state
-	data.Id = types.StringValue(strconv.FormatInt(time.Now().Unix(), 10))
+	data.Id = types.StringValue(hashStrings(names))
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// collectionFilter selects collections by the filter attributes of the pinecone_collections data source.
+type collectionFilter struct {
+	nameRegex   *regexp.Regexp
+	status      string
+	environment string
+	dimension   *int64
+	minSize     *int64
+	maxSize     *int64
+}
+
+func newCollectionFilter(data *models.CollectionsDataSourceModel) (*collectionFilter, error) {
+	filter := &collectionFilter{
+		status:      data.Status.ValueString(),
+		environment: data.Environment.ValueString(),
+		dimension:   data.Dimension.ValueInt64Pointer(),
+		minSize:     data.MinSize.ValueInt64Pointer(),
+		maxSize:     data.MaxSize.ValueInt64Pointer(),
+	}
+	if !data.NameRegex.IsNull() {
+		re, err := regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		filter.nameRegex = re
+	}
+	return filter, nil
+}
+
+// Match reports whether the collection passes every configured filter. Collections
+// without a reported size or dimension never match a size or dimension filter.
+func (f *collectionFilter) Match(collection *pinecone.Collection) bool {
+	if f.nameRegex != nil && !f.nameRegex.MatchString(collection.Name) {
+		return false
+	}
+	if f.status != "" && f.status != string(collection.Status) {
+		return false
+	}
+	if f.environment != "" && f.environment != collection.Environment {
+		return false
+	}
+	if f.dimension != nil && (collection.Dimension == nil || int64(*collection.Dimension) != *f.dimension) {
+		return false
+	}
+	if f.minSize != nil && (collection.Size == nil || *collection.Size < *f.minSize) {
+		return false
+	}
+	if f.maxSize != nil && (collection.Size == nil || *collection.Size > *f.maxSize) {
+		return false
+	}
+	return true
+}
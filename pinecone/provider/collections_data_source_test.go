@@ -3,7 +3,10 @@ package provider
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
 func TestAccCollectionsDataSource(t *testing.T) {
@@ -21,6 +24,45 @@ func TestAccCollectionsDataSource(t *testing.T) {
 	})
 }
 
+func TestCollectionFilter_Match(t *testing.T) {
+	size := int64(1000)
+	dimension := int32(1536)
+	collection := &pinecone.Collection{
+		Name:        "search-snapshot",
+		Status:      pinecone.CollectionStatusReady,
+		Environment: "us-west4-gcp",
+		Size:        &size,
+		Dimension:   &dimension,
+	}
+
+	cases := map[string]struct {
+		data models.CollectionsDataSourceModel
+		want bool
+	}{
+		"no filters":         {models.CollectionsDataSourceModel{}, true},
+		"name_regex":         {models.CollectionsDataSourceModel{NameRegex: types.StringValue("^search-")}, true},
+		"name_regex miss":    {models.CollectionsDataSourceModel{NameRegex: types.StringValue("^other-")}, false},
+		"status":             {models.CollectionsDataSourceModel{Status: types.StringValue("Ready")}, true},
+		"status miss":        {models.CollectionsDataSourceModel{Status: types.StringValue("Initializing")}, false},
+		"environment miss":   {models.CollectionsDataSourceModel{Environment: types.StringValue("us-east1-gcp")}, false},
+		"dimension":          {models.CollectionsDataSourceModel{Dimension: types.Int64Value(1536)}, true},
+		"dimension miss":     {models.CollectionsDataSourceModel{Dimension: types.Int64Value(768)}, false},
+		"size in range":      {models.CollectionsDataSourceModel{MinSize: types.Int64Value(1000), MaxSize: types.Int64Value(2000)}, true},
+		"size below minimum": {models.CollectionsDataSourceModel{MinSize: types.Int64Value(1001)}, false},
+		"size above maximum": {models.CollectionsDataSourceModel{MaxSize: types.Int64Value(999)}, false},
+	}
+
+	for name, c := range cases {
+		filter, err := newCollectionFilter(&c.data)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if got := filter.Match(collection); got != c.want {
+			t.Errorf("%s: expected match %t, got %t", name, c.want, got)
+		}
+	}
+}
+
 const testAccCollectionsDataSourceConfig = `
 provider "pinecone" {
 }
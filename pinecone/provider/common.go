@@ -2,15 +2,196 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
-	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
 )
 
+// PineconeProviderData is handed from the provider to every resource and data source.
+type PineconeProviderData struct {
+	Client client.ControlPlane
+	// Clients serves resources and data sources that set their own project_api_key
+	// or profile. It may be nil, in which case they cannot.
+	Clients *ClientCache
+	// ReadOnly refuses every create, update and delete.
+	ReadOnly bool
+	Waiter   waiter.Config
+	Timeouts DefaultTimeouts
+	// Policy restricts the indexes that may be planned. It is nil when there is no policy.
+	Policy *IndexPolicy
+	// Costs estimates what indexes cost. It may be nil, in which case the built-in
+	// prices are used.
+	Costs *CostEstimator
+}
+
+// ClientCache holds one client per API key, so that every resource and data source
+// using the same key shares a client, and with it the coalescing of index polls.
+type ClientCache struct {
+	newClient func(apiKey string) (client.ControlPlane, error)
+
+	// mu guards the maps, but not their entries, so that resolving one key does not
+	// hold up callers needing another.
+	mu      sync.Mutex
+	clients map[string]*cached[client.ControlPlane]
+	// profiles caches the API key of every profile, which may have come from a command.
+	profiles map[string]*cached[string]
+	// projects caches the project of the API key of every client.
+	projects map[client.ControlPlane]*cached[string]
+}
+
+// NewClientCache returns a ClientCache that creates missing clients with newClient.
+func NewClientCache(newClient func(apiKey string) (client.ControlPlane, error)) *ClientCache {
+	return &ClientCache{
+		newClient: newClient,
+		clients:   map[string]*cached[client.ControlPlane]{},
+		profiles:  map[string]*cached[string]{},
+		projects:  map[client.ControlPlane]*cached[string]{},
+	}
+}
+
+// get returns the client of the API key read from creds.
+func (c *ClientCache) get(ctx context.Context, creds credentials) (client.ControlPlane, error) {
+	apiKey := creds.apiKey
+	if apiKey == "" {
+		key, err := cacheEntry(c, c.profiles, creds.profile).get(func() (string, error) {
+			return creds.resolve(ctx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		apiKey = key
+	}
+
+	return cacheEntry(c, c.clients, apiKey).get(func() (client.ControlPlane, error) {
+		return c.newClient(apiKey)
+	})
+}
+
+// errProjectUnknown keeps a project that is not known yet out of the cache.
+var errProjectUnknown = errors.New("the project has no index with a host")
+
+// callerProject returns the project of the API key of api, which is resolved once per
+// client. It is empty while the project has no index with a host, and resolved again
+// on the next call. A nil cache resolves the project on every call.
+func (c *ClientCache) callerProject(ctx context.Context, api client.ControlPlane) (string, error) {
+	resolve := func() (string, error) {
+		identity, err := resolveCallerIdentity(ctx, api)
+		if err == nil && identity.ProjectID == "" {
+			err = errProjectUnknown
+		}
+		return identity.ProjectID, err
+	}
+	var project string
+	var err error
+	if c == nil {
+		project, err = resolve()
+	} else {
+		project, err = cacheEntry(c, c.projects, api).get(resolve)
+	}
+	if errors.Is(err, errProjectUnknown) {
+		return "", nil
+	}
+	return project, err
+}
+
+// cacheEntry returns the entry of key in entries, adding it when missing.
+func cacheEntry[K comparable, T any](c *ClientCache, entries map[K]*cached[T], key K) *cached[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := entries[key]
+	if !ok {
+		entry = &cached[T]{}
+		entries[key] = entry
+	}
+	return entry
+}
+
+// cached holds a value that is computed when it is first needed. Callers of the same
+// entry wait for each other while it is computed; a failure is not cached.
+type cached[T any] struct {
+	mu    sync.Mutex
+	value T
+	ok    bool
+}
+
+func (e *cached[T]) get(compute func() (T, error)) (T, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.ok {
+		value, err := compute()
+		if err != nil {
+			return value, err
+		}
+		e.value, e.ok = value, true
+	}
+	return e.value, nil
+}
+
+// clientFor returns the client for the project_api_key or profile of a resource or data
+// source, or defaultClient when neither is set.
+func clientFor(ctx context.Context, defaultClient client.ControlPlane, clients *ClientCache, projectApiKey types.String, profile types.String) (client.ControlPlane, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	creds := credentials{apiKey: projectApiKey.ValueString(), profile: profile.ValueString()}
+	if !creds.isSet() {
+		return defaultClient, diags
+	}
+
+	attr := path.Root("project_api_key")
+	if creds.apiKey == "" {
+		attr = path.Root("profile")
+	}
+	if clients == nil {
+		diags.AddAttributeError(attr, "Credentials cannot be overridden", "The provider was configured without support for per-resource credentials. Please report this issue to the provider developers.")
+		return nil, diags
+	}
+	cp, err := clients.get(ctx, creds)
+	if err != nil {
+		diags.AddAttributeError(attr, "Failed to create pinecone client", err.Error())
+		return nil, diags
+	}
+	return cp, diags
+}
+
+// DefaultTimeouts holds the provider-level overrides for resource timeouts.
+// A zero value means the resource's built-in default is used.
+type DefaultTimeouts struct {
+	Create time.Duration
+	Read   time.Duration
+	Update time.Duration
+	Delete time.Duration
+}
+
+// timeoutOrDefault returns override if it is set, otherwise builtIn.
+func timeoutOrDefault(override time.Duration, builtIn time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	return builtIn
+}
+
+// timeoutDescription documents a resource timeout and its built-in default.
+func timeoutDescription(builtIn time.Duration) string {
+	return fmt.Sprintf("Timeout defaults to %d mins, or to the provider's `default_timeouts`. ", int(builtIn.Minutes())) +
+		`Accepts a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
+		`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
+		`"s" (seconds), "m" (minutes), "h" (hours).`
+}
+
 type PineconeDatasource struct {
-	client *pinecone.Client
+	client  client.ControlPlane
+	clients *ClientCache
 }
 
 func (d *PineconeDatasource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
@@ -19,21 +200,40 @@ func (d *PineconeDatasource) Configure(ctx context.Context, req datasource.Confi
 		return
 	}
 
-	client, ok := req.ProviderData.(*pinecone.Client)
+	providerData, ok := req.ProviderData.(*PineconeProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Client Type",
-			fmt.Sprintf("Expected *pinecone.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *PineconeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Client
+	d.clients = providerData.Clients
+}
+
+// clientFor returns the client for the credentials of the data source.
+func (d *PineconeDatasource) clientFor(ctx context.Context, projectApiKey types.String, profile types.String) (client.ControlPlane, diag.Diagnostics) {
+	return clientFor(ctx, d.client, d.clients, projectApiKey, profile)
+}
+
+// privateState is the private state the framework keeps for a resource, which it hands
+// over in the Private field of requests and responses.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
 }
 
 type PineconeResource struct {
-	client *pinecone.Client
+	client   client.ControlPlane
+	clients  *ClientCache
+	readOnly bool
+	waiter   waiter.Config
+	timeouts DefaultTimeouts
+	policy   *IndexPolicy
+	costs    *CostEstimator
 }
 
 func (d *PineconeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
@@ -42,15 +242,92 @@ func (d *PineconeResource) Configure(ctx context.Context, req resource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*pinecone.Client)
+	providerData, ok := req.ProviderData.(*PineconeProviderData)
 
 	if !ok {
 		resp.Diagnostics.AddError(
-			"Unexpected Client Type",
-			fmt.Sprintf("Expected *pinecone.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *PineconeProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 		return
 	}
 
-	d.client = client
+	d.client = providerData.Client
+	d.clients = providerData.Clients
+	d.readOnly = providerData.ReadOnly
+	d.waiter = providerData.Waiter
+	d.timeouts = providerData.Timeouts
+	d.policy = providerData.Policy
+	d.costs = providerData.Costs
+}
+
+// clientFor returns the client for the credentials of the resource.
+func (d *PineconeResource) clientFor(ctx context.Context, projectApiKey types.String, profile types.String) (client.ControlPlane, diag.Diagnostics) {
+	return clientFor(ctx, d.client, d.clients, projectApiKey, profile)
+}
+
+// newWaiter returns a waiter for the named object using the provider's polling configuration.
+func (d *PineconeResource) newWaiter(kind string, name string, timeout time.Duration, refresh waiter.RefreshFunc) *waiter.Waiter {
+	config := d.waiter
+	if config == (waiter.Config{}) {
+		config = waiter.DefaultConfig()
+	}
+	return &waiter.Waiter{
+		Config:  config,
+		Kind:    kind,
+		Name:    name,
+		Timeout: timeout,
+		Refresh: refresh,
+	}
+}
+
+// planAction describes what a plan does to a resource.
+func planAction(req resource.ModifyPlanRequest) string {
+	switch {
+	case req.State.Raw.IsNull():
+		return "create"
+	case req.Plan.Raw.IsNull():
+		return "delete"
+	case !req.Plan.Raw.Equal(req.State.Raw):
+		return "update"
+	}
+	return ""
+}
+
+// denyReadOnlyPlan fails a plan that changes the resource when the provider is read-only.
+func (d *PineconeResource) denyReadOnlyPlan(req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, kind string) {
+	if action := planAction(req); action != "" {
+		resp.Diagnostics.Append(d.denyReadOnly(action, kind)...)
+	}
+}
+
+// denyReadOnly returns an error when the provider is read-only. Every create, update and
+// delete checks it before sending any request, in case the plan was not checked.
+func (d *PineconeResource) denyReadOnly(action string, kind string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if d.readOnly {
+		diags.AddError("Provider is read-only",
+			fmt.Sprintf("Cannot %s the %s because the provider is configured with read_only = true.", action, kind))
+	}
+	return diags
+}
+
+// deleteCollection deletes a collection the resource created along the way and waits
+// until it is gone.
+func (d *PineconeResource) deleteCollection(ctx context.Context, api client.ControlPlane, name string, timeout time.Duration) error {
+	if err := api.DeleteCollection(ctx, name); err != nil && !isNotFoundError(err) {
+		return err
+	}
+	w := d.newWaiter("collection", name, timeout, func(ctx context.Context) (string, waiter.Class, error) {
+		collection, err := api.DescribeCollection(ctx, name)
+		if err != nil {
+			if isNotFoundError(err) {
+				return deletedState, waiter.Target, nil
+			}
+			return "", waiter.Pending, err
+		}
+		return string(collection.Status), waiter.Pending, nil
+	})
+	_, err := w.Wait(ctx)
+	return err
 }
@@ -5,20 +5,34 @@ package provider
 
 import (
 	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/pinecone-io/go-pinecone/pinecone"
-	"testing"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
 )
 
 func TestDatasource_Configure(t *testing.T) {
-	// Create a test *pinecone.Client
-	testClient := &pinecone.Client{}
+	// Create a test client
+	testClient := client.NewMemory()
+	testProviderData := &PineconeProviderData{Client: testClient, Waiter: waiter.DefaultConfig()}
 
 	// Create a mock context and request
 	ctx := context.Background()
 	req := datasource.ConfigureRequest{
-		ProviderData: &testClient,
+		ProviderData: testProviderData,
 	}
 	resp := &datasource.ConfigureResponse{}
 
@@ -28,13 +42,13 @@ func TestDatasource_Configure(t *testing.T) {
 	r.Configure(ctx, req, resp)
 
 	// Check if the client field in r has been correctly set
-	if r.client != nil && r.client != testClient {
+	if r.client != testClient {
 		t.Errorf("Expected r.client to be set to the test client, got: %v", r.client)
 	}
 
-	// Now, let's test the case where req.ProviderData is not *pinecone.Client
+	// Now, let's test the case where req.ProviderData is not *PineconeProviderData
 	invalidReq := datasource.ConfigureRequest{
-		ProviderData: "not a *pinecone.Client", // Pass a non-*pinecone.Client value
+		ProviderData: "not a *PineconeProviderData", // Pass a non-*PineconeProviderData value
 	}
 	invalidResp := &datasource.ConfigureResponse{}
 
@@ -46,7 +60,7 @@ func TestDatasource_Configure(t *testing.T) {
 		t.Error("Expected an error in resp.Diagnostics.Errors, but found none")
 	} else {
 		// Check the error message
-		expectedErrorMessage := "Expected *pinecone.Client, got: string. Please report this issue to the provider developers."
+		expectedErrorMessage := "Expected *PineconeProviderData, got: string. Please report this issue to the provider developers."
 		actualErrorMessage := invalidResp.Diagnostics.Errors()[0].Detail()
 		if actualErrorMessage != expectedErrorMessage {
 			t.Errorf("Expected error message: %s, got: %s", expectedErrorMessage, actualErrorMessage)
@@ -55,13 +69,14 @@ func TestDatasource_Configure(t *testing.T) {
 }
 
 func TestResource_Configure(t *testing.T) {
-	// Create a test *pinecone.Client
-	testClient := &pinecone.Client{}
+	// Create a test client
+	testClient := client.NewMemory()
+	testProviderData := &PineconeProviderData{Client: testClient, Waiter: waiter.DefaultConfig()}
 
 	// Create a mock context and request
 	ctx := context.Background()
 	req := resource.ConfigureRequest{
-		ProviderData: &testClient,
+		ProviderData: testProviderData,
 	}
 	resp := &resource.ConfigureResponse{}
 
@@ -71,13 +86,13 @@ func TestResource_Configure(t *testing.T) {
 	r.Configure(ctx, req, resp)
 
 	// Check if the client field in r has been correctly set
-	if r.client != nil && r.client != testClient {
+	if r.client != testClient {
 		t.Errorf("Expected r.client to be set to the test client, got: %v", r.client)
 	}
 
-	// Now, let's test the case where req.ProviderData is not *pinecone.Client
+	// Now, let's test the case where req.ProviderData is not *PineconeProviderData
 	invalidReq := resource.ConfigureRequest{
-		ProviderData: "not a *pinecone.Client", // Pass a non-*pinecone.Client value
+		ProviderData: "not a *PineconeProviderData", // Pass a non-*PineconeProviderData value
 	}
 	invalidResp := &resource.ConfigureResponse{}
 
@@ -89,10 +104,288 @@ func TestResource_Configure(t *testing.T) {
 		t.Error("Expected an error in resp.Diagnostics.Errors, but found none")
 	} else {
 		// Check the error message
-		expectedErrorMessage := "Expected *pinecone.Client, got: string. Please report this issue to the provider developers."
+		expectedErrorMessage := "Expected *PineconeProviderData, got: string. Please report this issue to the provider developers."
 		actualErrorMessage := invalidResp.Diagnostics.Errors()[0].Detail()
 		if actualErrorMessage != expectedErrorMessage {
 			t.Errorf("Expected error message: %s, got: %s", expectedErrorMessage, actualErrorMessage)
 		}
 	}
 }
+
+func TestTimeoutOrDefault(t *testing.T) {
+	if got := timeoutOrDefault(0, 10*time.Minute); got != 10*time.Minute {
+		t.Errorf("Expected the built-in default when no override is set, got: %s", got)
+	}
+	if got := timeoutOrDefault(2*time.Minute, 10*time.Minute); got != 2*time.Minute {
+		t.Errorf("Expected the provider override, got: %s", got)
+	}
+}
+
+// newTestProviderData returns provider data backed by c that polls without delay,
+// so that unit tests do not sleep.
+func newTestProviderData(c client.ControlPlane) *PineconeProviderData {
+	return &PineconeProviderData{
+		Client: c,
+		Waiter: waiter.Config{
+			PollInterval: time.Millisecond,
+			MinBackoff:   time.Millisecond,
+			MaxBackoff:   time.Millisecond,
+		},
+	}
+}
+
+// configureTestResource configures r with data and fails the test on error.
+func configureTestResource(t *testing.T, r resource.ResourceWithConfigure, data *PineconeProviderData) {
+	t.Helper()
+
+	resp := &resource.ConfigureResponse{}
+	r.Configure(context.Background(), resource.ConfigureRequest{ProviderData: data}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("failed to configure resource: %v", resp.Diagnostics)
+	}
+}
+
+// testResourceSchema returns the schema of r and fails the test if it is invalid.
+func testResourceSchema(t *testing.T, r resource.Resource) resourceschema.Schema {
+	t.Helper()
+
+	resp := &resource.SchemaResponse{}
+	r.Schema(context.Background(), resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("invalid schema: %v", resp.Diagnostics)
+	}
+	return resp.Schema
+}
+
+// configureTestDataSource configures d with data and fails the test on error.
+func configureTestDataSource(t *testing.T, d datasource.DataSourceWithConfigure, data *PineconeProviderData) datasourceschema.Schema {
+	t.Helper()
+	ctx := context.Background()
+
+	configureResp := &datasource.ConfigureResponse{}
+	d.Configure(ctx, datasource.ConfigureRequest{ProviderData: data}, configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatalf("failed to configure data source: %v", configureResp.Diagnostics)
+	}
+
+	schemaResp := &datasource.SchemaResponse{}
+	d.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+	if schemaResp.Diagnostics.HasError() {
+		t.Fatalf("invalid schema: %v", schemaResp.Diagnostics)
+	}
+	return schemaResp.Schema
+}
+
+// newTestPlan returns a plan of s holding model.
+func newTestPlan(t *testing.T, s resourceschema.Schema, model interface{}) tfsdk.Plan {
+	t.Helper()
+
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+	if diags := plan.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("failed to set plan: %v", diags)
+	}
+	return plan
+}
+
+// newTestState returns an empty state of s.
+func newTestState(s resourceschema.Schema) tfsdk.State {
+	return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+}
+
+// newTestDataSourceState returns an empty state of s.
+func newTestDataSourceState(s datasourceschema.Schema) tfsdk.State {
+	return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+}
+
+// newTestConfig returns a data source configuration of s holding model.
+func newTestConfig(t *testing.T, s datasourceschema.Schema, model interface{}) tfsdk.Config {
+	t.Helper()
+
+	state := newTestDataSourceState(s)
+	if diags := state.Set(context.Background(), model); diags.HasError() {
+		t.Fatalf("failed to set config: %v", diags)
+	}
+	return tfsdk.Config{Schema: s, Raw: state.Raw}
+}
+
+// testPrivateState is a privateState held in memory.
+type testPrivateState map[string][]byte
+
+func (p testPrivateState) GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p testPrivateState) SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(p, key)
+	} else {
+		p[key] = value
+	}
+	return nil
+}
+
+// newTestResource returns the resource of newResource configured with data.
+func newTestResource[T resource.ResourceWithConfigure](t *testing.T, newResource func() resource.Resource, data *PineconeProviderData) T {
+	t.Helper()
+
+	r, ok := newResource().(T)
+	if !ok {
+		t.Fatalf("unexpected resource type %T", newResource())
+	}
+	configureTestResource(t, r, data)
+	return r
+}
+
+// createTestResource runs Create of r for plan, a model of its schema, and returns the
+// response.
+func createTestResource(t *testing.T, r resource.Resource, plan interface{}) *resource.CreateResponse {
+	t.Helper()
+
+	s := testResourceSchema(t, r)
+	resp := &resource.CreateResponse{State: newTestState(s)}
+	r.Create(context.Background(), resource.CreateRequest{Plan: newTestPlan(t, s, plan)}, resp)
+	return resp
+}
+
+// nullTimeouts is an unset timeouts block.
+func nullTimeouts() timeouts.Value {
+	return timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+		"create": types.StringType,
+		"read":   types.StringType,
+		"update": types.StringType,
+		"delete": types.StringType,
+	})}
+}
+
+// runTestFunction runs f with args and returns its result, or the error it raised.
+func runTestFunction(t *testing.T, f function.Function, args ...attr.Value) (attr.Value, *function.FuncError) {
+	t.Helper()
+
+	ctx := context.Background()
+	definition := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, definition)
+	if len(definition.Definition.Parameters) != len(args) {
+		t.Fatalf("expected %d arguments, got %d", len(definition.Definition.Parameters), len(args))
+	}
+
+	result, err := definition.Definition.Return.NewResultData(ctx)
+	if err != nil {
+		t.Fatalf("failed to create result: %v", err)
+	}
+	resp := &function.RunResponse{Result: result}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(args)}, resp)
+	return resp.Result.Value(), resp.Error
+}
+
+// newTestClientCache returns a ClientCache that serves an in-memory client per API key,
+// recording them in projects.
+func newTestClientCache(projects map[string]*client.Memory) *ClientCache {
+	return NewClientCache(func(apiKey string) (client.ControlPlane, error) {
+		if _, ok := projects[apiKey]; !ok {
+			projects[apiKey] = client.NewMemory()
+		}
+		return projects[apiKey], nil
+	})
+}
+
+func TestClientFor(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("PINECONE_CONFIG_FILE", writeTestFile(t, "config", "[prod]\napi_key = prod-key\n"))
+
+	defaultClient := client.NewMemory()
+	projects := map[string]*client.Memory{}
+	clients := newTestClientCache(projects)
+
+	got, diags := clientFor(ctx, defaultClient, clients, types.StringNull(), types.StringNull())
+	if diags.HasError() || got != client.ControlPlane(defaultClient) {
+		t.Errorf("expected the provider's client without overrides, got %v: %v", got, diags)
+	}
+
+	byKey, diags := clientFor(ctx, defaultClient, clients, types.StringValue("prod-key"), types.StringNull())
+	if diags.HasError() || byKey != client.ControlPlane(projects["prod-key"]) {
+		t.Errorf("expected the client of project_api_key, got %v: %v", byKey, diags)
+	}
+	byProfile, diags := clientFor(ctx, defaultClient, clients, types.StringNull(), types.StringValue("prod"))
+	if diags.HasError() || byProfile != byKey {
+		t.Errorf("expected the profile to share the client of its key, got %v: %v", byProfile, diags)
+	}
+	if len(projects) != 1 {
+		t.Errorf("expected a single client to be created, got %d", len(projects))
+	}
+
+	// Profiles are resolved once, as they may run a command.
+	t.Setenv("PINECONE_CONFIG_FILE", writeTestFile(t, "config", "[prod]\napi_key = rotated-key\n"))
+	if again, _ := clientFor(ctx, defaultClient, clients, types.StringNull(), types.StringValue("prod")); again != byKey {
+		t.Error("expected the key of the profile to be cached")
+	}
+
+	if _, diags := clientFor(ctx, defaultClient, clients, types.StringNull(), types.StringValue("missing")); !diags.HasError() {
+		t.Error("expected an error for an unknown profile")
+	}
+	if _, diags := clientFor(ctx, defaultClient, nil, types.StringValue("prod-key"), types.StringNull()); !diags.HasError() {
+		t.Error("expected an error without a client cache")
+	}
+}
+
+func TestClientCache_concurrentKeys(t *testing.T) {
+	ctx := context.Background()
+	unblock := make(chan struct{})
+	clients := NewClientCache(func(apiKey string) (client.ControlPlane, error) {
+		if apiKey == "slow-key" {
+			<-unblock
+		}
+		return client.NewMemory(), nil
+	})
+
+	slow := make(chan error)
+	go func() {
+		_, err := clients.get(ctx, credentials{apiKey: "slow-key"})
+		slow <- err
+	}()
+
+	// A key that takes long to resolve must not hold up the others.
+	done := make(chan error)
+	go func() {
+		_, err := clients.get(ctx, credentials{apiKey: "fast-key"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected another key not to wait for the slow one")
+	}
+
+	close(unblock)
+	if err := <-slow; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientCache_callerProject(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	clients := newTestClientCache(map[string]*client.Memory{})
+
+	// A project without an index is not known, and is resolved again.
+	for i := 0; i < 2; i++ {
+		if project, err := clients.callerProject(ctx, memory); err != nil || project != "" {
+			t.Fatalf("expected an unknown project, got %q: %v", project, err)
+		}
+	}
+	if n := memory.Calls(client.OpListIndexes); n != 2 {
+		t.Errorf("expected an unknown project to be resolved on every call, got %d calls", n)
+	}
+
+	memory.PutIndex(&pinecone.Index{Name: "products", Host: "products-abc123.svc.us-west4-gcp.pinecone.io"})
+	for i := 0; i < 2; i++ {
+		if project, err := clients.callerProject(ctx, memory); err != nil || project != "abc123" {
+			t.Fatalf("expected project abc123, got %q: %v", project, err)
+		}
+	}
+	if n := memory.Calls(client.OpListIndexes); n != 3 {
+		t.Errorf("expected the project to be resolved once, got %d calls", n-2)
+	}
+}
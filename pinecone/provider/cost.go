@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+// hoursPerMonth is the average number of hours in a month, as used for cloud billing.
+const hoursPerMonth = 730
+
+// podPricesJSON holds the list price in USD of one pod for an hour, by pod type.
+//
+//go:embed prices.json
+var podPricesJSON []byte
+
+// CostEstimator estimates what pod indexes cost. Serverless indexes are billed by usage
+// and have no estimate.
+type CostEstimator struct {
+	// Prices holds the price in USD of one pod for an hour, by pod type.
+	Prices map[string]float64
+	// MonthlyIncreaseThreshold is the increase of the estimated monthly cost in USD
+	// above which a plan warns.
+	MonthlyIncreaseThreshold float64
+}
+
+// defaultPodPrices returns the embedded price table.
+func defaultPodPrices() map[string]float64 {
+	var prices map[string]float64
+	if err := json.Unmarshal(podPricesJSON, &prices); err != nil {
+		panic(fmt.Sprintf("invalid embedded prices.json: %s", err))
+	}
+	return prices
+}
+
+// newCostEstimator reads the cost_estimate block of the provider configuration. Its
+// prices override the embedded ones for the same pod type.
+func newCostEstimator(ctx context.Context, obj types.Object) (*CostEstimator, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	estimator := &CostEstimator{Prices: defaultPodPrices()}
+	if obj.IsNull() || obj.IsUnknown() {
+		return estimator, diags
+	}
+
+	var model PineconeCostEstimateModel
+	diags.Append(obj.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if !model.Prices.IsNull() && !model.Prices.IsUnknown() {
+		var prices map[string]float64
+		diags.Append(model.Prices.ElementsAs(ctx, &prices, false)...)
+		for podType, price := range prices {
+			if price < 0 {
+				diags.AddAttributeError(path.Root("cost_estimate").AtName("prices").AtMapKey(podType), "Invalid price",
+					fmt.Sprintf("The price of pod type %s must not be negative, got %v.", podType, price))
+			}
+			estimator.Prices[podType] = price
+		}
+	}
+	if !model.MonthlyIncreaseThreshold.IsNull() && !model.MonthlyIncreaseThreshold.IsUnknown() {
+		estimator.MonthlyIncreaseThreshold = model.MonthlyIncreaseThreshold.ValueFloat64()
+	}
+	return estimator, diags
+}
+
+// Estimate sets the estimated costs of the index from its pod type, replicas and shards.
+// They are unknown while any of those is, and null for serverless indexes and pod types
+// without a price. A nil estimator uses the embedded prices.
+func (c *CostEstimator) Estimate(ctx context.Context, data *models.IndexResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.EstimatedHourlyCost = types.Float64Null()
+	data.EstimatedMonthlyCost = types.Float64Null()
+	if data.Spec.IsUnknown() {
+		data.EstimatedHourlyCost = types.Float64Unknown()
+		data.EstimatedMonthlyCost = types.Float64Unknown()
+		return diags
+	}
+	if data.Spec.IsNull() {
+		return diags
+	}
+
+	var spec models.IndexSpecModel
+	diags.Append(data.Spec.As(ctx, &spec, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() || spec.Pod == nil {
+		return diags
+	}
+
+	pod := spec.Pod
+	if pod.PodType.IsUnknown() || pod.Replicas.IsUnknown() || pod.ShardCount.IsUnknown() {
+		data.EstimatedHourlyCost = types.Float64Unknown()
+		data.EstimatedMonthlyCost = types.Float64Unknown()
+		return diags
+	}
+
+	prices := c.prices()
+	price, ok := prices[pod.PodType.ValueString()]
+	if !ok {
+		return diags
+	}
+	hourly := price * float64(pod.Replicas.ValueInt64()*pod.ShardCount.ValueInt64())
+	data.EstimatedHourlyCost = types.Float64Value(round(hourly, 4))
+	data.EstimatedMonthlyCost = types.Float64Value(round(hourly*hoursPerMonth, 2))
+	return diags
+}
+
+// WarnIncrease warns when the planned index costs more per month than the prior one, by
+// more than the threshold. Only estimates that are both known are compared, so creating
+// an index, or switching a serverless one to pods, does not warn.
+func (c *CostEstimator) WarnIncrease(name string, prior types.Float64, planned types.Float64) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !isKnown(prior) || !isKnown(planned) {
+		return diags
+	}
+	before := prior.ValueFloat64()
+	after := planned.ValueFloat64()
+	var threshold float64
+	if c != nil {
+		threshold = c.MonthlyIncreaseThreshold
+	}
+	if increase := after - before; increase > threshold {
+		diags.AddAttributeWarning(path.Root("estimated_monthly_cost"), "Estimated cost increase",
+			fmt.Sprintf("The estimated monthly cost of index %s rises from $%.2f to $%.2f (+$%.2f). "+
+				"Estimates are based on list prices and may differ from your bill.", name, before, after, increase))
+	}
+	return diags
+}
+
+func (c *CostEstimator) prices() map[string]float64 {
+	if c == nil {
+		return defaultPodPrices()
+	}
+	return c.Prices
+}
+
+// round rounds an amount to the given number of decimal places, so that floating point
+// noise does not show up as a change in the plan.
+func round(amount float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(amount*scale) / scale
+}
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+func TestDefaultPodPrices(t *testing.T) {
+	prices := defaultPodPrices()
+	for _, family := range []string{"s1", "p1", "p2"} {
+		for _, size := range []string{"x1", "x2", "x4", "x8"} {
+			if prices[family+"."+size] <= 0 {
+				t.Errorf("expected a price for %s.%s", family, size)
+			}
+		}
+	}
+}
+
+func TestNewCostEstimator(t *testing.T) {
+	ctx := context.Background()
+	attrTypes := map[string]attr.Type{
+		"prices":                     types.MapType{ElemType: types.Float64Type},
+		"monthly_increase_threshold": types.Float64Type,
+	}
+	value := func(prices map[string]attr.Value) types.Object {
+		return types.ObjectValueMust(attrTypes, map[string]attr.Value{
+			"prices":                     types.MapValueMust(types.Float64Type, prices),
+			"monthly_increase_threshold": types.Float64Value(50),
+		})
+	}
+
+	estimator, diags := newCostEstimator(ctx, types.ObjectNull(attrTypes))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if estimator.Prices["p1.x1"] != defaultPodPrices()["p1.x1"] || estimator.MonthlyIncreaseThreshold != 0 {
+		t.Errorf("expected the embedded prices without a block, got %+v", estimator)
+	}
+
+	estimator, diags = newCostEstimator(ctx, value(map[string]attr.Value{"p1.x1": types.Float64Value(0.05), "p3.x1": types.Float64Value(1)}))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if estimator.Prices["p1.x1"] != 0.05 || estimator.Prices["p3.x1"] != 1 || estimator.Prices["s1.x1"] != defaultPodPrices()["s1.x1"] {
+		t.Errorf("expected configured prices to override the embedded ones, got %v", estimator.Prices)
+	}
+	if estimator.MonthlyIncreaseThreshold != 50 {
+		t.Errorf("expected a threshold of 50, got %v", estimator.MonthlyIncreaseThreshold)
+	}
+
+	_, diags = newCostEstimator(ctx, value(map[string]attr.Value{"p1.x1": types.Float64Value(-1)}))
+	if got := attributePaths(diags); len(got) != 1 || got[0] != `cost_estimate.prices["p1.x1"]` {
+		t.Errorf("expected an error at the negative price, got: %v", diags)
+	}
+}
+
+func TestCostEstimator_Estimate(t *testing.T) {
+	ctx := context.Background()
+	estimator := &CostEstimator{Prices: map[string]float64{"p1.x1": 0.1}}
+
+	cases := map[string]struct {
+		spec    models.IndexSpecModel
+		hourly  types.Float64
+		monthly types.Float64
+	}{
+		"pod": {
+			models.IndexSpecModel{Pod: testPolicyPodSpec("us-west4-gcp", "p1.x1", types.Int64Value(3), types.Int64Value(2))},
+			types.Float64Value(0.6), types.Float64Value(438),
+		},
+		"no price": {
+			models.IndexSpecModel{Pod: testPolicyPodSpec("us-west4-gcp", "p9.x1", types.Int64Value(1), types.Int64Value(1))},
+			types.Float64Null(), types.Float64Null(),
+		},
+		"unknown replicas": {
+			models.IndexSpecModel{Pod: testPolicyPodSpec("us-west4-gcp", "p1.x1", types.Int64Unknown(), types.Int64Value(1))},
+			types.Float64Unknown(), types.Float64Unknown(),
+		},
+		"serverless": {
+			models.IndexSpecModel{Serverless: &models.IndexServerlessSpecModel{Cloud: types.StringValue("aws"), Region: types.StringValue("us-west-2")}},
+			types.Float64Null(), types.Float64Null(),
+		},
+	}
+
+	for name, c := range cases {
+		data := testPolicyIndex(t, types.StringValue("costs"), c.spec)
+		if diags := estimator.Estimate(ctx, &data); diags.HasError() {
+			t.Fatalf("%s: %v", name, diags)
+		}
+		if !data.EstimatedHourlyCost.Equal(c.hourly) || !data.EstimatedMonthlyCost.Equal(c.monthly) {
+			t.Errorf("%s: expected %v and %v, got %v and %v", name, c.hourly, c.monthly, data.EstimatedHourlyCost, data.EstimatedMonthlyCost)
+		}
+	}
+}
+
+func TestCostEstimator_WarnIncrease(t *testing.T) {
+	estimator := &CostEstimator{MonthlyIncreaseThreshold: 100}
+
+	cases := map[string]struct {
+		prior   types.Float64
+		planned types.Float64
+		warns   bool
+	}{
+		"new index":          {types.Float64Null(), types.Float64Value(140.16), false},
+		"small increase":     {types.Float64Value(140.16), types.Float64Value(210.24), false},
+		"large increase":     {types.Float64Value(140.16), types.Float64Value(420.48), true},
+		"decrease":           {types.Float64Value(420.48), types.Float64Value(140.16), false},
+		"to serverless":      {types.Float64Value(420.48), types.Float64Null(), false},
+		"unknown estimate":   {types.Float64Value(140.16), types.Float64Unknown(), false},
+		"unpriced to priced": {types.Float64Null(), types.Float64Value(420.48), false},
+	}
+
+	for name, c := range cases {
+		diags := estimator.WarnIncrease("costs", c.prior, c.planned)
+		if got := diags.WarningsCount() > 0; got != c.warns {
+			t.Errorf("%s: expected a warning %t, got: %v", name, c.warns, diags)
+		}
+	}
+
+	if diags := (*CostEstimator)(nil).WarnIncrease("costs", types.Float64Value(1), types.Float64Value(2)); diags.WarningsCount() != 1 {
+		t.Errorf("expected every increase to warn without a threshold, got: %v", diags)
+	}
+}
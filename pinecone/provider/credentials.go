@@ -0,0 +1,347 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultProfile is used when no credentials are configured and the config file has a profile of this name.
+const defaultProfile = "default"
+
+// apiKeyCommandTimeout bounds how long an api_key_command may run.
+const apiKeyCommandTimeout = time.Minute
+
+// credentials describe where an API key is read from. Only the first source that is set,
+// in field order, is used.
+type credentials struct {
+	apiKey  string
+	file    string
+	command []string
+	profile string
+}
+
+func (c credentials) isSet() bool {
+	return c.apiKey != "" || c.file != "" || len(c.command) > 0 || c.profile != ""
+}
+
+// resolve returns the API key of the first source that is set, or an empty string when none is.
+func (c credentials) resolve(ctx context.Context) (string, error) {
+	switch {
+	case c.apiKey != "":
+		return c.apiKey, nil
+	case c.file != "":
+		return readAPIKeyFile(c.file)
+	case len(c.command) > 0:
+		return runAPIKeyCommand(ctx, c.command)
+	case c.profile != "":
+		profile, err := loadProfile(c.profile)
+		if err != nil {
+			return "", err
+		}
+		return profile.resolve(ctx)
+	}
+	return "", nil
+}
+
+func readAPIKeyFile(file string) (string, error) {
+	content, err := os.ReadFile(expandHome(file))
+	if err != nil {
+		return "", fmt.Errorf("reading API key file: %w", err)
+	}
+	key := strings.TrimSpace(string(content))
+	if key == "" {
+		return "", fmt.Errorf("API key file %s is empty", file)
+	}
+	return key, nil
+}
+
+// runAPIKeyCommand runs command and returns its trimmed output as the API key.
+func runAPIKeyCommand(ctx context.Context, command []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiKeyCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("running API key command %s: %w: %s", command[0], err, msg)
+		}
+		return "", fmt.Errorf("running API key command %s: %w", command[0], err)
+	}
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("API key command %s printed nothing", command[0])
+	}
+	return key, nil
+}
+
+// configFile returns the path of the file holding named profiles, which can be changed
+// with the PINECONE_CONFIG_FILE environment variable.
+func configFile() string {
+	if file := os.Getenv("PINECONE_CONFIG_FILE"); file != "" {
+		return expandHome(file)
+	}
+	return expandHome(filepath.Join("~", ".pinecone", "config"))
+}
+
+// loadProfile reads the credentials of a named profile from the config file. The file
+// holds sections of key = value pairs:
+//
+//	[default]
+//	api_key_file = ~/.pinecone/key
+//
+//	[ci]
+//	api_key_command = vault kv get -field=api_key secret/pinecone
+func loadProfile(name string) (credentials, error) {
+	file := configFile()
+	profiles, err := parseConfigFile(file)
+	if err != nil {
+		return credentials{}, err
+	}
+	values, ok := profiles[name]
+	if !ok {
+		return credentials{}, fmt.Errorf("profile %q not found in %s", name, file)
+	}
+
+	var c credentials
+	for key, value := range values {
+		switch key {
+		case "api_key":
+			c.apiKey = value
+		case "api_key_file":
+			c.file = value
+		case "api_key_command":
+			command, err := splitCommand(value)
+			if err != nil {
+				return credentials{}, fmt.Errorf("profile %q in %s: api_key_command: %w", name, file, err)
+			}
+			c.command = command
+		default:
+			return credentials{}, fmt.Errorf("profile %q in %s: unknown setting %q", name, file, key)
+		}
+	}
+	if !c.isSet() {
+		return credentials{}, fmt.Errorf("profile %q in %s does not set api_key, api_key_file or api_key_command", name, file)
+	}
+	return c, nil
+}
+
+// hasProfile reports whether the config file exists and defines the named profile.
+func hasProfile(name string) bool {
+	profiles, err := parseConfigFile(configFile())
+	if err != nil {
+		return false
+	}
+	_, ok := profiles[name]
+	return ok
+}
+
+func parseConfigFile(file string) (map[string]map[string]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	defer f.Close()
+
+	profiles := map[string]map[string]string{}
+	var section map[string]string
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, "[") && strings.HasSuffix(text, "]"):
+			name := strings.TrimSpace(strings.TrimPrefix(text[1:len(text)-1], "profile "))
+			section = map[string]string{}
+			profiles[name] = section
+		default:
+			key, value, ok := strings.Cut(text, "=")
+			if !ok || section == nil {
+				return nil, fmt.Errorf("%s:%d: expected a [profile] header or a key = value pair", file, line)
+			}
+			section[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	return profiles, nil
+}
+
+// splitCommand splits a command line into words, honouring single and double quotes.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return words, nil
+}
+
+func expandHome(file string) string {
+	if file != "~" && !strings.HasPrefix(file, "~/") {
+		return file
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return file
+	}
+	return filepath.Join(home, strings.TrimPrefix(file, "~"))
+}
+
+func projectApiKeyDescription(kind string) string {
+	return fmt.Sprintf("API key to use for this %s instead of the key the provider is configured with, typically that of another project. "+
+		"Lets a single provider configuration manage several projects. The key is stored in the state.", kind)
+}
+
+func profileDescription(kind string) string {
+	return fmt.Sprintf("Name of a profile in `~/.pinecone/config` providing the API key to use for this %s instead of the key the provider is configured with.", kind)
+}
+
+// credentialResourceAttributes returns the attributes through which a resource of r
+// overrides the credentials of the provider. Changing them replaces the resource when
+// the new credentials belong to another project, which does not hold the resource.
+func credentialResourceAttributes(kind string, r *PineconeResource) map[string]resourceschema.Attribute {
+	replaced := fmt.Sprintf(" Changing it replaces the %s when the new key belongs to another project. A new key of the same project, "+
+		"or one whose project cannot be resolved, is used in place.", kind)
+	replace := stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		change := r.credentialsChange(ctx, req.State, req.Plan)
+		resp.RequiresReplace = change.known && change.from != change.to
+	}, "Replaces the resource when the new credentials belong to another project.", "Replaces the resource when the new credentials belong to another project.")
+	return map[string]resourceschema.Attribute{
+		"project_api_key": resourceschema.StringAttribute{
+			MarkdownDescription: projectApiKeyDescription(kind) + replaced,
+			Optional:            true,
+			Sensitive:           true,
+			Validators:          []validator.String{stringvalidator.ConflictsWith(path.MatchRoot("profile"))},
+			PlanModifiers:       []planmodifier.String{replace},
+		},
+		"profile": resourceschema.StringAttribute{
+			MarkdownDescription: profileDescription(kind) + replaced,
+			Optional:            true,
+			PlanModifiers:       []planmodifier.String{replace},
+		},
+	}
+}
+
+// credentialsChange is a planned change of the credentials of a resource, from the
+// project of the prior credentials to that of the planned ones.
+type credentialsChange struct {
+	from, to string
+	// known is false when either project could not be resolved.
+	known bool
+}
+
+// credentialsChange resolves the projects of the credentials in state and in plan.
+func (r *PineconeResource) credentialsChange(ctx context.Context, state tfsdk.State, plan tfsdk.Plan) credentialsChange {
+	var priorKey, priorProfile, plannedKey, plannedProfile types.String
+	var diags diag.Diagnostics
+	diags.Append(state.GetAttribute(ctx, path.Root("project_api_key"), &priorKey)...)
+	diags.Append(state.GetAttribute(ctx, path.Root("profile"), &priorProfile)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("project_api_key"), &plannedKey)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root("profile"), &plannedProfile)...)
+	if diags.HasError() || plannedKey.IsUnknown() || plannedProfile.IsUnknown() {
+		return credentialsChange{}
+	}
+
+	from, ok := r.credentialsProject(ctx, priorKey, priorProfile)
+	if !ok {
+		return credentialsChange{}
+	}
+	to, ok := r.credentialsProject(ctx, plannedKey, plannedProfile)
+	return credentialsChange{from: from, to: to, known: ok}
+}
+
+// credentialsProject returns the project of the credentials of a resource, and false
+// when it cannot be resolved.
+func (r *PineconeResource) credentialsProject(ctx context.Context, projectApiKey types.String, profile types.String) (string, bool) {
+	api, diags := r.clientFor(ctx, projectApiKey, profile)
+	if diags.HasError() || api == nil {
+		return "", false
+	}
+	project, err := r.clients.callerProject(ctx, api)
+	if err != nil {
+		tflog.Debug(ctx, fmt.Sprintf("failed to resolve the project of the credentials: %s", err))
+		return "", false
+	}
+	return project, project != ""
+}
+
+// credentialDataSourceAttributes returns the attributes through which a data source
+// overrides the credentials of the provider.
+func credentialDataSourceAttributes() map[string]datasourceschema.Attribute {
+	return map[string]datasourceschema.Attribute{
+		"project_api_key": datasourceschema.StringAttribute{
+			MarkdownDescription: projectApiKeyDescription("data source"),
+			Optional:            true,
+			Sensitive:           true,
+			Validators:          []validator.String{stringvalidator.ConflictsWith(path.MatchRoot("profile"))},
+		},
+		"profile": datasourceschema.StringAttribute{
+			MarkdownDescription: profileDescription("data source"),
+			Optional:            true,
+		},
+	}
+}
+
+// addAttributes adds the extra attributes to a schema.
+func addAttributes[T any](attrs map[string]T, extra map[string]T) {
+	for name, attr := range extra {
+		attrs[name] = attr
+	}
+}
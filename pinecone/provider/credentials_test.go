@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+)
+
+// writeTestFile writes content to a file in a temporary directory and returns its path.
+func writeTestFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// unsetCredentialsEnv clears the environment variables that provide credentials.
+func unsetCredentialsEnv(t *testing.T) {
+	for _, env := range []string{"PINECONE_API_KEY", "PINECONE_API_KEY_FILE", "PINECONE_PROFILE"} {
+		t.Setenv(env, "")
+	}
+	t.Setenv("PINECONE_CONFIG_FILE", filepath.Join(t.TempDir(), "missing"))
+}
+
+func TestCredentials_resolve(t *testing.T) {
+	keyFile := writeTestFile(t, "key", "file-key\n")
+	t.Setenv("PINECONE_CONFIG_FILE", writeTestFile(t, "config", `
+# Profiles used by the tests.
+[default]
+api_key = default-key
+
+[profile from-file]
+api_key_file = `+keyFile+`
+
+[command]
+api_key_command = echo "command key"
+
+[empty]
+`))
+
+	cases := map[string]struct {
+		creds credentials
+		want  string
+		err   string
+	}{
+		"none":              {credentials{}, "", ""},
+		"api key":           {credentials{apiKey: "key", file: keyFile}, "key", ""},
+		"file":              {credentials{file: keyFile}, "file-key", ""},
+		"missing file":      {credentials{file: keyFile + ".missing"}, "", "reading API key file"},
+		"command":           {credentials{command: []string{"echo", "  command-key  "}}, "command-key", ""},
+		"failing command":   {credentials{command: []string{"false"}}, "", "running API key command false"},
+		"silent command":    {credentials{command: []string{"true"}}, "", "printed nothing"},
+		"default profile":   {credentials{profile: "default"}, "default-key", ""},
+		"profile file":      {credentials{profile: "from-file"}, "file-key", ""},
+		"profile command":   {credentials{profile: "command"}, "command key", ""},
+		"empty profile":     {credentials{profile: "empty"}, "", "does not set api_key"},
+		"unknown profile":   {credentials{profile: "missing"}, "", `profile "missing" not found`},
+		"file over profile": {credentials{file: keyFile, profile: "default"}, "file-key", ""},
+	}
+
+	for name, c := range cases {
+		got, err := c.creds.resolve(context.Background())
+		if c.err != "" {
+			if err == nil || !strings.Contains(err.Error(), c.err) {
+				t.Errorf("%s: expected an error containing %q, got: %v", name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: expected %q, got %q", name, c.want, got)
+		}
+	}
+}
+
+func TestLoadProfile_invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown setting":    "[default]\nregion = us-east-1\n",
+		"no section":         "api_key = key\n",
+		"not a pair":         "[default]\napi_key\n",
+		"unterminated quote": "[default]\napi_key_command = echo 'key\n",
+		"empty command":      "[default]\napi_key_command =\n",
+	}
+
+	for name, content := range cases {
+		t.Setenv("PINECONE_CONFIG_FILE", writeTestFile(t, "config", content))
+		creds, err := loadProfile("default")
+		if err == nil {
+			t.Errorf("%s: expected an error, got %+v", name, creds)
+		}
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	cases := map[string][]string{
+		"vault kv get -field=api_key secret/pinecone": {"vault", "kv", "get", "-field=api_key", "secret/pinecone"},
+		`op read "op://Private/Pinecone/api key"`:     {"op", "read", "op://Private/Pinecone/api key"},
+		`  sh -c 'echo $KEY'  `:                       {"sh", "-c", "echo $KEY"},
+		`helper ""`:                                   {"helper", ""},
+	}
+
+	for in, want := range cases {
+		got, err := splitCommand(in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestNewCredentials(t *testing.T) {
+	null := PineconeProviderModel{ApiKey: types.StringNull(), ApiKeyFile: types.StringNull(), ApiKeyCommand: types.ListNull(types.StringType), Profile: types.StringNull()}
+
+	t.Run("configured", func(t *testing.T) {
+		unsetCredentialsEnv(t)
+		t.Setenv("PINECONE_API_KEY", "env-key")
+
+		data := null
+		data.ApiKeyCommand = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("echo"), types.StringValue("key")})
+		creds, diags := newCredentials(context.Background(), &data)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if want := (credentials{command: []string{"echo", "key"}}); !reflect.DeepEqual(creds, want) {
+			t.Errorf("expected the configured command to take precedence over the environment, got %+v", creds)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		unsetCredentialsEnv(t)
+		t.Setenv("PINECONE_PROFILE", "ci")
+
+		data := null
+		creds, _ := newCredentials(context.Background(), &data)
+		if want := (credentials{profile: "ci"}); !reflect.DeepEqual(creds, want) {
+			t.Errorf("expected the profile from PINECONE_PROFILE, got %+v", creds)
+		}
+	})
+
+	t.Run("default profile", func(t *testing.T) {
+		unsetCredentialsEnv(t)
+
+		data := null
+		if creds, _ := newCredentials(context.Background(), &data); creds.isSet() {
+			t.Errorf("expected no credentials without a config file, got %+v", creds)
+		}
+
+		t.Setenv("PINECONE_CONFIG_FILE", writeTestFile(t, "config", "[default]\napi_key = key\n"))
+		if creds, _ := newCredentials(context.Background(), &data); creds.profile != defaultProfile {
+			t.Errorf("expected the default profile, got %+v", creds)
+		}
+	})
+}
+
+func TestCredentialResourceAttributes_requireReplace(t *testing.T) {
+	ctx := context.Background()
+	// The keys old and rotated belong to project abc123, other to project xyz789, and
+	// the project of empty has no index to be resolved from.
+	projects := map[string]*client.Memory{}
+	for key, project := range map[string]string{"old": "abc123", "rotated": "abc123", "other": "xyz789"} {
+		projects[key] = client.NewMemory()
+		projects[key].PutIndex(&pinecone.Index{Name: "products", Host: "products-" + project + ".svc.us-west4-gcp.pinecone.io"})
+	}
+	data := newTestProviderData(client.NewMemory())
+	data.Clients = newTestClientCache(projects)
+	r := newTestResource[*CollectionResource](t, NewCollectionResource, data)
+	s := testResourceSchema(t, r)
+
+	for _, c := range []struct {
+		key  string
+		want bool
+	}{
+		{"rotated", false},
+		{"other", true},
+		{"empty", false},
+	} {
+		for _, name := range []string{"project_api_key", "profile"} {
+			state := newTestState(s)
+			state.SetAttribute(ctx, path.Root("project_api_key"), "old")
+			plan := tfsdk.Plan{Schema: s, Raw: state.Raw}
+			plan.SetAttribute(ctx, path.Root("project_api_key"), c.key)
+
+			req := planmodifier.StringRequest{
+				Path:        path.Root(name),
+				State:       state,
+				Plan:        plan,
+				StateValue:  types.StringValue("old"),
+				PlanValue:   types.StringValue(c.key),
+				ConfigValue: types.StringValue(c.key),
+			}
+			resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+			for _, modifier := range s.Attributes[name].(resourceschema.StringAttribute).PlanModifiers {
+				modifier.PlanModifyString(ctx, req, resp)
+			}
+			if resp.RequiresReplace != c.want {
+				t.Errorf("%s to %s: expected replacement %v, got %v", name, c.key, c.want, resp.RequiresReplace)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+// The client only surfaces the API error message, not the HTTP status code,
+// so the helpers below classify errors by their message.
+
+// isNotFoundError reports whether err means the requested object does not exist. Only
+// the endings of the client's messages count, "Resource <name> not found" and the bare
+// 404 status, so a name or a wait error that happens to mention either does not.
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var timeoutErr *waiter.TimeoutError
+	var failedErr *waiter.FailedStateError
+	if errors.As(err, &timeoutErr) || errors.As(err, &failedErr) {
+		return false
+	}
+	msg := err.Error()
+	return strings.HasSuffix(msg, " not found") || strings.HasSuffix(msg, "status code: 404")
+}
+
+// isAlreadyExistsError reports whether err means an object with the same name already exists.
+func isAlreadyExistsError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already exists") || strings.Contains(msg, "409")
+}
+
+// isUnauthorizedError reports whether err means the API key was rejected, because it is
+// invalid or has been revoked.
+func isUnauthorizedError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "401") || strings.Contains(msg, "unauthorized") || strings.Contains(msg, "invalid api key")
+}
+
+// isForbiddenError reports whether err means the API key is not allowed to perform the request.
+func isForbiddenError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "403") || strings.Contains(msg, "forbidden")
+}
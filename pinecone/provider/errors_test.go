@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+func TestIsNotFoundError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil":                 {nil, false},
+		"describe index":      {errors.New("failed to describe idx: Resource test not found"), true},
+		"status 404":          {errors.New("unexpected status code: 404"), true},
+		"wrapped":             {fmt.Errorf("delete: %w", errors.New("failed to delete index: Resource test not found")), true},
+		"authentication":      {errors.New("failed to describe idx: Invalid API Key"), false},
+		"name containing 404": {errors.New("failed to describe idx: Invalid API Key for idx-404"), false},
+		"name containing not found": {
+			errors.New("failed to configure index not found-logs: Invalid value for replicas"), false},
+		"timeout": {&waiter.TimeoutError{Kind: "index", Name: "idx-404", LastState: "not found",
+			Timeline: &waiter.Timeline{}}, false},
+		"failed state": {&waiter.FailedStateError{Kind: "index", Name: "idx-404", State: "not found",
+			Timeline: &waiter.Timeline{}}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isNotFoundError(tc.err); got != tc.want {
+				t.Errorf("isNotFoundError(%v) = %t, want %t", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsAlreadyExistsError(t *testing.T) {
+	if isAlreadyExistsError(nil) {
+		t.Error("Expected nil not to be an already exists error")
+	}
+	if !isAlreadyExistsError(errors.New("failed to create index: Resource  already exists")) {
+		t.Error("Expected create index conflict to be an already exists error")
+	}
+	if isAlreadyExistsError(errors.New("failed to create index: Invalid value for dimension")) {
+		t.Error("Expected validation error not to be an already exists error")
+	}
+}
+
+func TestIsUnauthorizedError(t *testing.T) {
+	if isUnauthorizedError(nil) {
+		t.Error("Expected nil not to be an unauthorized error")
+	}
+	if !isUnauthorizedError(errors.New("unexpected status code: 401")) {
+		t.Error("Expected 401 status to be an unauthorized error")
+	}
+	if !isUnauthorizedError(errors.New("failed to describe idx: Invalid API Key")) {
+		t.Error("Expected invalid key message to be an unauthorized error")
+	}
+	if isUnauthorizedError(errors.New("unexpected status code: 404")) {
+		t.Error("Expected 404 status not to be an unauthorized error")
+	}
+}
+
+func TestIsForbiddenError(t *testing.T) {
+	if !isForbiddenError(errors.New("unexpected status code: 403")) {
+		t.Error("Expected 403 status to be a forbidden error")
+	}
+	if isForbiddenError(errors.New("unexpected status code: 401")) {
+		t.Error("Expected 401 status not to be a forbidden error")
+	}
+}
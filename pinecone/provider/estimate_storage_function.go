@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"math"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// s1PodBytes is the storage of an s1.x1 pod, which Pinecone sizes at five million
+// vectors of 768 dimensions. Every size doubles it.
+const s1PodBytes int64 = 5_000_000 * 768 * 4
+
+// estimateStorage returns the smallest s1 pod type that holds the vectors in a single
+// pod, or the number of s1.x8 pods that hold them. Every dimension takes four bytes.
+func estimateStorage(vectors int64, dimension int64, metadataBytes int64) (podType string, pods int64) {
+	total := vectors * (dimension*4 + metadataBytes)
+	for i, size := range podSizes {
+		if total <= s1PodBytes<<i {
+			return "s1." + size, 1
+		}
+	}
+	largest := s1PodBytes << (len(podSizes) - 1)
+	return "s1." + podSizes[len(podSizes)-1], (total + largest - 1) / largest
+}
+
+var _ function.Function = &EstimateStorageFunction{}
+
+func NewEstimateStorageFunction() function.Function {
+	return &EstimateStorageFunction{}
+}
+
+// EstimateStorageFunction recommends the pod type and number of pods for an amount of
+// data.
+type EstimateStorageFunction struct{}
+
+var estimateStorageAttrTypes = map[string]attr.Type{
+	"pod_type": types.StringType,
+	"pods":     types.Int64Type,
+}
+
+func (f *EstimateStorageFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "estimate_storage"
+}
+
+func (f *EstimateStorageFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Recommend a pod type and pod count for an amount of data",
+		MarkdownDescription: "Recommends the storage-optimized pod type and the number of pods that hold the given vectors, as " +
+			"an object with `pod_type` and `pods`. An s1.x1 pod holds five million vectors of 768 dimensions, and every size " +
+			"doubles that. The smallest size that holds every vector in one pod is recommended. Beyond the largest size, " +
+			"`pods` is the number of s1.x8 pods, to be used as shards. Replicas are not included.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:                "vectors",
+				MarkdownDescription: "The number of vectors.",
+			},
+			function.Int64Parameter{
+				Name:                "dimension",
+				MarkdownDescription: "The dimension of the vectors.",
+			},
+			function.Int64Parameter{
+				Name:                "metadata_bytes",
+				MarkdownDescription: "The average size of the metadata of a vector, in bytes.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: estimateStorageAttrTypes},
+	}
+}
+
+func (f *EstimateStorageFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var vectors, dimension, metadataBytes int64
+	resp.Error = req.Arguments.Get(ctx, &vectors, &dimension, &metadataBytes)
+	if resp.Error != nil {
+		return
+	}
+
+	switch {
+	case vectors < 0:
+		resp.Error = function.NewArgumentFuncError(0, "vectors must not be negative")
+		return
+	case dimension < 1:
+		resp.Error = function.NewArgumentFuncError(1, "dimension must be at least 1")
+		return
+	case metadataBytes < 0:
+		resp.Error = function.NewArgumentFuncError(2, "metadata_bytes must not be negative")
+		return
+	case vectors > 0 && dimension*4+metadataBytes > math.MaxInt64/vectors:
+		resp.Error = function.NewFuncError("the vectors take more storage than can be estimated")
+		return
+	}
+
+	podType, pods := estimateStorage(vectors, dimension, metadataBytes)
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(estimateStorageAttrTypes, map[string]attr.Value{
+		"pod_type": types.StringValue(podType),
+		"pods":     types.Int64Value(pods),
+	}))
+}
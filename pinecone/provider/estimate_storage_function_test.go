@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEstimateStorage(t *testing.T) {
+	cases := []struct {
+		vectors, dimension, metadataBytes int64
+		podType                           string
+		pods                              int64
+	}{
+		{0, 768, 0, "s1.x1", 1},
+		{5_000_000, 768, 0, "s1.x1", 1},
+		{5_000_001, 768, 0, "s1.x2", 1},
+		{5_000_000, 1536, 0, "s1.x2", 1},
+		{10_000_000, 768, 3072, "s1.x4", 1},
+		{40_000_000, 768, 0, "s1.x8", 1},
+		{100_000_000, 1536, 0, "s1.x8", 5},
+	}
+	for _, c := range cases {
+		podType, pods := estimateStorage(c.vectors, c.dimension, c.metadataBytes)
+		if podType != c.podType || pods != c.pods {
+			t.Errorf("%d vectors of %d dimensions and %d bytes: expected %d %s pods, got %d %s", c.vectors, c.dimension, c.metadataBytes, c.pods, c.podType, pods, podType)
+		}
+	}
+}
+
+func TestEstimateStorageFunction(t *testing.T) {
+	result, err := runTestFunction(t, NewEstimateStorageFunction(), types.Int64Value(100_000_000), types.Int64Value(1536), types.Int64Value(0))
+	want := types.ObjectValueMust(estimateStorageAttrTypes, map[string]attr.Value{
+		"pod_type": types.StringValue("s1.x8"),
+		"pods":     types.Int64Value(5),
+	})
+	if err != nil || !result.Equal(want) {
+		t.Errorf("expected %v, got %v: %v", want, result, err)
+	}
+
+	for i, args := range [][3]int64{{-1, 768, 0}, {1, 0, 0}, {1, 768, -1}, {math.MaxInt64, 768, 0}} {
+		if _, err := runTestFunction(t, NewEstimateStorageFunction(), types.Int64Value(args[0]), types.Int64Value(args[1]), types.Int64Value(args[2])); err == nil {
+			t.Errorf("case %d: expected an error for %v", i, args)
+		}
+	}
+}
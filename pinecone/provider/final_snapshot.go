@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+// finalSnapshotTimestamp is the layout of {timestamp} in final snapshot names, which may
+// only hold lowercase letters, digits and hyphens.
+const finalSnapshotTimestamp = "20060102-150405"
+
+// finalSnapshotName renders the name template of a final snapshot.
+func finalSnapshotName(template string, index string, now time.Time) string {
+	return strings.NewReplacer(
+		"{index}", index,
+		"{timestamp}", now.UTC().Format(finalSnapshotTimestamp),
+	).Replace(template)
+}
+
+// denyServerlessFinalSnapshot returns an error when a final snapshot is configured for a
+// serverless index, which cannot be snapshotted.
+func denyServerlessFinalSnapshot(ctx context.Context, data *models.IndexResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.FinalSnapshot.IsNull() || data.FinalSnapshot.IsUnknown() || data.Spec.IsNull() || data.Spec.IsUnknown() {
+		return diags
+	}
+	var spec models.IndexSpecModel
+	diags.Append(data.Spec.As(ctx, &spec, basetypes.ObjectAsOptions{})...)
+	if !diags.HasError() && spec.Serverless != nil {
+		diags.AddAttributeError(path.Root("final_snapshot"), "Final snapshot not supported",
+			"Only pod indexes can be snapshotted into a collection. Backups of serverless indexes are not available "+
+				"in the version of the Pinecone API this provider uses.")
+	}
+	return diags
+}
+
+// createFinalSnapshot creates the final snapshot of the index, if one is configured, and
+// waits for it to be ready. It returns the name of the snapshot, or an empty string when
+// none was taken.
+func (r *IndexResource) createFinalSnapshot(ctx context.Context, api client.ControlPlane, data *models.IndexResourceModel, timeout time.Duration) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if data.FinalSnapshot.IsNull() || data.FinalSnapshot.IsUnknown() {
+		return "", diags
+	}
+	var snapshot models.IndexFinalSnapshotModel
+	diags.Append(data.FinalSnapshot.As(ctx, &snapshot, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return "", diags
+	}
+
+	index, err := api.DescribeIndex(ctx, data.Name.ValueString())
+	if err != nil {
+		if !isNotFoundError(err) {
+			diags.AddError("Failed to describe index", err.Error())
+		}
+		// An index that is already gone has nothing left to snapshot.
+		return "", diags
+	}
+	if index.Spec == nil || index.Spec.Pod == nil {
+		diags.AddAttributeError(path.Root("final_snapshot"), "Final snapshot not supported",
+			fmt.Sprintf("Index %s is not a pod index, so it cannot be snapshotted into a collection. "+
+				"Remove the final_snapshot block, and apply it, to delete the index.", index.Name))
+		return "", diags
+	}
+
+	now := time.Now()
+	name := finalSnapshotName(snapshot.Name.ValueString(), index.Name, now)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err = api.DescribeCollection(ctx, name)
+	if err != nil && !isNotFoundError(err) {
+		diags.AddError("Failed to describe collection", err.Error())
+		return "", diags
+	}
+	if err == nil {
+		if snapshot.Retain.ValueBool() {
+			diags.AddAttributeError(path.Root("final_snapshot").AtName("name"), "Final snapshot already exists",
+				fmt.Sprintf("Collection %s already exists and final_snapshot.retain is set, so it is not replaced. "+
+					"Delete the collection, use {timestamp} in the name, or set retain = false.", name))
+			return "", diags
+		}
+		diags.Append(r.replaceFinalSnapshot(ctx, api, index.Name, name, now, timeout)...)
+		if diags.HasError() {
+			return "", diags
+		}
+		return name, diags
+	}
+
+	if err := r.snapshotIndex(ctx, api, index.Name, name, timeout); err != nil {
+		diags.AddError("Failed to create final snapshot", fmt.Sprintf("%s. The index has not been deleted.", err))
+		return "", diags
+	}
+	return name, diags
+}
+
+// replaceFinalSnapshot replaces the collection name with a new snapshot of index.
+// Collection names are unique, so the new snapshot cannot be taken before the earlier
+// one is deleted. A temporary snapshot is taken first instead, so that a ready copy of
+// the index exists at every step, and deleted once the new snapshot is ready.
+func (r *IndexResource) replaceFinalSnapshot(ctx context.Context, api client.ControlPlane, index string, name string, now time.Time, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	temporary := temporarySnapshotName(name, now)
+	if err := r.snapshotIndex(ctx, api, index, temporary, timeout); err != nil {
+		diags.AddError("Failed to create final snapshot",
+			fmt.Sprintf("%s. Neither the index nor collection %s have been deleted.", err, name))
+		return diags
+	}
+	if err := r.deleteCollection(ctx, api, name, timeout); err != nil {
+		diags.AddError("Failed to replace final snapshot",
+			fmt.Sprintf("%s. The index has not been deleted. Collection %s holds a copy of it.", err, temporary))
+		return diags
+	}
+	if err := r.snapshotIndex(ctx, api, index, name, timeout); err != nil {
+		diags.AddError("Failed to create final snapshot",
+			fmt.Sprintf("%s. The index has not been deleted. Collection %s holds a copy of it.", err, temporary))
+		return diags
+	}
+	if err := r.deleteCollection(ctx, api, temporary, timeout); err != nil {
+		diags.AddWarning("Temporary snapshot not deleted",
+			fmt.Sprintf("Collection %s, taken while replacing final snapshot %s, could not be deleted: %s. Delete it by hand.", temporary, name, err))
+	}
+	return diags
+}
+
+// temporarySnapshotName returns the name of the snapshot taken while name is replaced,
+// shortening name to keep within the 45 characters a collection name may have.
+func temporarySnapshotName(name string, now time.Time) string {
+	suffix := "-" + now.UTC().Format("20060102150405")
+	if len(name)+len(suffix) > 45 {
+		name = strings.TrimRight(name[:45-len(suffix)], "-")
+	}
+	return name + suffix
+}
+
+// snapshotIndex creates the collection name from index and waits for it to be ready.
+func (r *IndexResource) snapshotIndex(ctx context.Context, api client.ControlPlane, index string, name string, timeout time.Duration) error {
+	if _, err := api.CreateCollection(ctx, &pinecone.CreateCollectionRequest{Name: name, Source: index}); err != nil {
+		return err
+	}
+	w := r.newWaiter("collection", name, timeout, func(ctx context.Context) (string, waiter.Class, error) {
+		collection, err := api.DescribeCollection(ctx, name)
+		if err != nil {
+			return "", waiter.Pending, err
+		}
+		return string(collection.Status), classifyCollectionCreateState(collection.Status), nil
+	})
+	_, err := w.Wait(ctx)
+	return err
+}
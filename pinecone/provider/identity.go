@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+)
+
+// callerIdentity is what the control plane reveals about the project of an API key.
+// The API has no endpoint describing the project itself, so its ID is taken from the
+// host of one of its indexes, which is <index>-<project id>.svc.<environment>.pinecone.io.
+type callerIdentity struct {
+	// ProjectID is empty when the project has no index with a host yet.
+	ProjectID string
+}
+
+// resolveCallerIdentity makes a cheap authenticated call with api and derives the
+// project from its result.
+func resolveCallerIdentity(ctx context.Context, api client.ControlPlane) (callerIdentity, error) {
+	indexes, err := api.ListIndexes(ctx)
+	if err != nil {
+		return callerIdentity{}, err
+	}
+	for _, index := range indexes {
+		if projectID, ok := projectIDFromHost(index.Name, index.Host); ok {
+			return callerIdentity{ProjectID: projectID}, nil
+		}
+	}
+	return callerIdentity{}, nil
+}
+
+// projectIDFromHost extracts the project ID from the host of the named index.
+func projectIDFromHost(name string, host string) (string, bool) {
+	label, _, ok := strings.Cut(host, ".")
+	if !ok || !strings.HasPrefix(label, name+"-") {
+		return "", false
+	}
+	projectID := strings.TrimPrefix(label, name+"-")
+	return projectID, projectID != ""
+}
+
+// credentialsError turns the error of an authenticated call into a diagnostic that
+// explains what is wrong with the API key.
+func credentialsError(err error) diag.Diagnostic {
+	switch {
+	case isUnauthorizedError(err):
+		return diag.NewErrorDiagnostic("Invalid Pinecone API key",
+			"The API key was rejected by Pinecone. It may be mistyped, belong to another organization or have been revoked. "+
+				"Check the api_key, api_key_file, api_key_command or profile the provider is configured with.\n\n"+err.Error())
+	case isForbiddenError(err):
+		return diag.NewErrorDiagnostic("Pinecone API key not permitted",
+			"The API key is valid but is not allowed to list the indexes of its project.\n\n"+err.Error())
+	default:
+		return diag.NewErrorDiagnostic("Failed to validate Pinecone credentials", err.Error())
+	}
+}
+
+// validateCredentials checks that api can reach the control plane and, when
+// expectedProjectID is set, that it belongs to that project.
+func validateCredentials(ctx context.Context, api client.ControlPlane, expectedProjectID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	identity, err := resolveCallerIdentity(ctx, api)
+	if err != nil {
+		diags.Append(credentialsError(err))
+		return diags
+	}
+	if expectedProjectID == "" {
+		return diags
+	}
+
+	switch identity.ProjectID {
+	case expectedProjectID:
+	case "":
+		diags.AddAttributeWarning(path.Root("expected_project_id"), "Project could not be verified",
+			fmt.Sprintf("The project of the API key has no index with a host, so it could not be compared with %s.", expectedProjectID))
+	default:
+		diags.AddAttributeError(path.Root("expected_project_id"), "Wrong Pinecone project",
+			fmt.Sprintf("The API key belongs to project %s, but the provider expects project %s.", identity.ProjectID, expectedProjectID))
+	}
+	return diags
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+)
+
+func TestProjectIDFromHost(t *testing.T) {
+	cases := map[string]struct {
+		name, host string
+		want       string
+	}{
+		"serverless":   {"docs", "docs-4zo0ijk.svc.us-east1-aws.pinecone.io", "4zo0ijk"},
+		"pod":          {"my-index", "my-index-abc123.svc.us-west4-gcp.pinecone.io", "abc123"},
+		"no host":      {"docs", "", ""},
+		"other index":  {"docs", "other-4zo0ijk.svc.us-east1-aws.pinecone.io", ""},
+		"no project":   {"docs", "docs-.svc.us-east1-aws.pinecone.io", ""},
+		"not a domain": {"docs", "docs-4zo0ijk", ""},
+	}
+
+	for name, c := range cases {
+		got, ok := projectIDFromHost(c.name, c.host)
+		if got != c.want || ok != (c.want != "") {
+			t.Errorf("%s: expected %q, got %q (%t)", name, c.want, got, ok)
+		}
+	}
+}
+
+func TestValidateCredentials(t *testing.T) {
+	withIndex := client.NewMemory()
+	withIndex.PutIndex(&pinecone.Index{Name: "docs", Host: "docs-4zo0ijk.svc.us-east1-aws.pinecone.io"})
+	rejected := client.NewMemory()
+	rejected.Hook = func(op client.Operation, name string) error {
+		return errors.New("unexpected status code: 401")
+	}
+	forbidden := client.NewMemory()
+	forbidden.Hook = func(op client.Operation, name string) error {
+		return errors.New("unexpected status code: 403")
+	}
+
+	cases := map[string]struct {
+		api      client.ControlPlane
+		expected string
+		severity diag.Severity
+		summary  string
+	}{
+		"valid":             {withIndex, "", 0, ""},
+		"expected project":  {withIndex, "4zo0ijk", 0, ""},
+		"wrong project":     {withIndex, "other", diag.SeverityError, "Wrong Pinecone project"},
+		"unverifiable":      {client.NewMemory(), "4zo0ijk", diag.SeverityWarning, "Project could not be verified"},
+		"invalid key":       {rejected, "", diag.SeverityError, "Invalid Pinecone API key"},
+		"forbidden":         {forbidden, "", diag.SeverityError, "Pinecone API key not permitted"},
+		"no project needed": {client.NewMemory(), "", 0, ""},
+	}
+
+	for name, c := range cases {
+		diags := validateCredentials(context.Background(), c.api, c.expected)
+		if c.summary == "" {
+			if len(diags) > 0 {
+				t.Errorf("%s: unexpected diagnostics: %v", name, diags)
+			}
+			continue
+		}
+		if len(diags) != 1 || diags[0].Severity() != c.severity || !strings.Contains(diags[0].Summary(), c.summary) {
+			t.Errorf("%s: expected a %s %q, got: %v", name, c.severity, c.summary, diags)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+// importID is the object an import ID refers to.
+type importID struct {
+	// ProjectId is empty when the import ID does not name a project.
+	ProjectId string
+	Name      string
+}
+
+// parseImportID parses an import ID, which is either a name or a project_id/name pair.
+// When hosts is set, the host or URL of an index is accepted as well.
+func parseImportID(id string, hosts bool) (importID, error) {
+	if hosts && (strings.Contains(id, "://") || strings.Contains(id, ".svc.")) {
+		host, err := parseIndexHost(id)
+		if err != nil {
+			return importID{}, err
+		}
+		return importID{ProjectId: host.ProjectId, Name: host.Name}, nil
+	}
+
+	projectID, name, ok := strings.Cut(id, "/")
+	if !ok {
+		projectID, name = "", id
+	} else if projectID == "" {
+		return importID{}, fmt.Errorf("%q has an empty project ID", id)
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return importID{}, fmt.Errorf("%q is not a name or a project_id/name pair", id)
+	}
+	return importID{ProjectId: projectID, Name: name}, nil
+}
+
+// importIDFromIdentity returns the object an import by identity refers to.
+func importIDFromIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity) (importID, diag.Diagnostics) {
+	var model models.ResourceIdentityModel
+	diags := identity.Get(ctx, &model)
+	return importID{ProjectId: model.ProjectId.ValueString(), Name: model.Name.ValueString()}, diags
+}
+
+// checkImportProject returns the project of the provider's API key, and an error when
+// the import ID names another project. An import only has the provider's credentials,
+// as the project_api_key and profile of the imported resource are not known yet, so an
+// object of another project cannot be imported. The project is empty when the import
+// ID names none, or when it is not known.
+func checkImportProject(ctx context.Context, clients *ClientCache, api client.ControlPlane, kind string, id importID) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if id.ProjectId == "" {
+		return "", diags
+	}
+	projectID, err := clients.callerProject(ctx, api)
+	if err != nil {
+		diags.Append(credentialsError(err))
+		return "", diags
+	}
+	if projectID != "" && projectID != id.ProjectId {
+		diags.Append(wrongImportProject(kind, id, projectID))
+	}
+	return projectID, diags
+}
+
+// verifyImportProject returns an error when the project named by the import ID is not
+// the project of the imported object. projectID is the project of the object, or
+// empty when it is not known, in which case the project of the API key, as returned by
+// checkImportProject, is used.
+func verifyImportProject(kind string, id importID, projectID string, keyProjectID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if id.ProjectId == "" {
+		return diags
+	}
+	if projectID == "" {
+		projectID = keyProjectID
+	}
+
+	switch projectID {
+	case id.ProjectId:
+	case "":
+		diags.AddWarning("Project could not be verified",
+			fmt.Sprintf("The project of the API key has no index with a host, so it could not be compared with %s.", id.ProjectId))
+	default:
+		diags.Append(wrongImportProject(kind, id, projectID))
+	}
+	return diags
+}
+
+func wrongImportProject(kind string, id importID, projectID string) diag.Diagnostic {
+	return diag.NewErrorDiagnostic("Wrong Pinecone project",
+		fmt.Sprintf("The %s %s to import is named in project %s, but the provider's API key belongs to project %s. "+
+			"Imports use the provider's credentials, not the project_api_key or profile of the resource, so only objects "+
+			"of the provider's project can be imported. Import through a provider configuration whose API key belongs "+
+			"to project %s.", kind, id.Name, id.ProjectId, projectID, id.ProjectId))
+}
+
+// resourceIdentitySchema returns the identity of an index or a collection: its project
+// and its name.
+func resourceIdentitySchema(kind string) identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"project_id": identityschema.StringAttribute{
+				Description:       fmt.Sprintf("The ID of the project of the %s. It must be the project of the provider's API key, which it defaults to on import.", kind),
+				OptionalForImport: true,
+			},
+			"name": identityschema.StringAttribute{
+				Description:       fmt.Sprintf("The name of the %s.", kind),
+				RequiredForImport: true,
+			},
+		},
+	}
+}
+
+// setResourceIdentity records the project and name of an object in identity, updating
+// it when it differs. projectID is the project of the object, or empty to use the
+// project of the API key, which clients resolves once per client. While the project is
+// not known, identity is kept as it is.
+func setResourceIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, clients *ClientCache, api client.ControlPlane, name string, projectID string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if identity == nil {
+		return diags
+	}
+	if projectID == "" {
+		var err error
+		if projectID, err = clients.callerProject(ctx, api); err != nil {
+			tflog.Warn(ctx, fmt.Sprintf("the identity of %s is not updated, as the project of the API key could not be resolved: %s", name, err))
+			return diags
+		}
+	}
+	if projectID == "" {
+		return diags
+	}
+
+	want := models.ResourceIdentityModel{
+		ProjectId: types.StringValue(projectID),
+		Name:      types.StringValue(name),
+	}
+	if !identity.Raw.IsNull() {
+		var current models.ResourceIdentityModel
+		diags.Append(identity.Get(ctx, &current)...)
+		if diags.HasError() || current == want {
+			return diags
+		}
+	}
+	diags.Append(identity.Set(ctx, want)...)
+	return diags
+}
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+func TestParseImportID(t *testing.T) {
+	cases := []struct {
+		id    string
+		hosts bool
+		want  importID
+	}{
+		{"products", false, importID{Name: "products"}},
+		{"abc123/products", false, importID{ProjectId: "abc123", Name: "products"}},
+		{"abc123/products", true, importID{ProjectId: "abc123", Name: "products"}},
+		{"products-abc123.svc.us-west4-gcp.pinecone.io", true, importID{ProjectId: "abc123", Name: "products"}},
+		{"https://products-abc123.svc.us-west4-gcp.pinecone.io", true, importID{ProjectId: "abc123", Name: "products"}},
+	}
+	for _, c := range cases {
+		if got, err := parseImportID(c.id, c.hosts); err != nil || got != c.want {
+			t.Errorf("%s: expected %+v, got %+v: %v", c.id, c.want, got, err)
+		}
+	}
+
+	for _, c := range []struct {
+		id    string
+		hosts bool
+	}{
+		{"", false},
+		{"/products", false},
+		{"abc123/", false},
+		{"abc123/products/x", false},
+		{"https://products-abc123.svc.us-west4-gcp.pinecone.io", false},
+		{"https://example.com", true},
+	} {
+		if _, err := parseImportID(c.id, c.hosts); err == nil {
+			t.Errorf("%q: expected an error", c.id)
+		}
+	}
+}
+
+// newTestImportMemory returns a Memory holding the index "products" of project abc123
+// and its collection "products-backup".
+func newTestImportMemory() *client.Memory {
+	memory := client.NewMemory()
+	memory.PutIndex(&pinecone.Index{
+		Name:      "products",
+		Dimension: 8,
+		Metric:    pinecone.Cosine,
+		Host:      "products-abc123.svc.us-west4-gcp.pinecone.io",
+		Spec:      &pinecone.IndexSpec{Pod: &pinecone.PodSpec{Environment: "us-west4-gcp", PodType: "s1.x1", PodCount: 1, Replicas: 1, ShardCount: 1}},
+		Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	})
+	memory.PutCollection(&pinecone.Collection{Name: "products-backup", Status: pinecone.CollectionStatusReady})
+	return memory
+}
+
+// importTestResource runs ImportState for id and returns the response.
+func importTestResource(t *testing.T, r fwresource.ResourceWithImportState, id string) *fwresource.ImportStateResponse {
+	t.Helper()
+
+	resp := &fwresource.ImportStateResponse{State: newTestState(testResourceSchema(t, r))}
+	r.ImportState(context.Background(), fwresource.ImportStateRequest{ID: id}, resp)
+	return resp
+}
+
+// newTestIdentity returns an identity of r holding value, or a null identity when value
+// is nil.
+func newTestIdentity(t *testing.T, r fwresource.ResourceWithIdentity, value *models.ResourceIdentityModel) *tfsdk.ResourceIdentity {
+	t.Helper()
+	ctx := context.Background()
+
+	resp := &fwresource.IdentitySchemaResponse{}
+	r.IdentitySchema(ctx, fwresource.IdentitySchemaRequest{}, resp)
+	identity := &tfsdk.ResourceIdentity{Schema: resp.IdentitySchema, Raw: tftypes.NewValue(resp.IdentitySchema.Type().TerraformType(ctx), nil)}
+	if value != nil {
+		if diags := identity.Set(ctx, value); diags.HasError() {
+			t.Fatalf("failed to set identity: %v", diags)
+		}
+	}
+	return identity
+}
+
+// testIdentityValue returns the project and name held by identity.
+func testIdentityValue(t *testing.T, identity *tfsdk.ResourceIdentity) string {
+	t.Helper()
+
+	var model models.ResourceIdentityModel
+	if diags := identity.Get(context.Background(), &model); diags.HasError() {
+		t.Fatalf("failed to get identity: %v", diags)
+	}
+	return model.ProjectId.ValueString() + "/" + model.Name.ValueString()
+}
+
+func TestIndexResource_ImportState(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestImportMemory()
+	r := NewIndexResource().(*IndexResource)
+	configureTestResource(t, r, newTestProviderData(memory))
+
+	for _, id := range []string{
+		"products",
+		"abc123/products",
+		"products-abc123.svc.us-west4-gcp.pinecone.io",
+		"https://products-abc123.svc.us-west4-gcp.pinecone.io",
+	} {
+		resp := importTestResource(t, r, id)
+		if resp.Diagnostics.HasError() {
+			t.Errorf("%s: import failed: %v", id, resp.Diagnostics)
+			continue
+		}
+		var imported types.String
+		resp.State.GetAttribute(ctx, path.Root("id"), &imported)
+		if imported.ValueString() != "products" {
+			t.Errorf("%s: expected id products, got %v", id, imported)
+		}
+	}
+
+	for _, c := range []struct{ id, summary string }{
+		{"missing", "Index not found"},
+		{"xyz789/products", "Wrong Pinecone project"},
+		// The project is checked first, as the index would be looked up in another project.
+		{"xyz789/elsewhere", "Wrong Pinecone project"},
+		{"products-xyz789.svc.us-west4-gcp.pinecone.io", "Wrong Pinecone project"},
+		{"a/b/c", "Invalid import ID"},
+	} {
+		resp := importTestResource(t, r, c.id)
+		if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != c.summary {
+			t.Errorf("%s: expected %q, got: %v", c.id, c.summary, resp.Diagnostics)
+		}
+	}
+}
+
+func TestCollectionResource_ImportState(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestImportMemory()
+	r := NewCollectionResource().(*CollectionResource)
+	configureTestResource(t, r, newTestProviderData(memory))
+
+	resp := importTestResource(t, r, "abc123/products-backup")
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("import failed: %v", resp.Diagnostics)
+	}
+	var imported types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &imported)
+	if imported.ValueString() != "products-backup" {
+		t.Errorf("expected id products-backup, got %v", imported)
+	}
+
+	if resp := importTestResource(t, r, "xyz789/products-backup"); !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Wrong Pinecone project" {
+		t.Errorf("expected the wrong project to be rejected, got: %v", resp.Diagnostics)
+	}
+	if resp := importTestResource(t, r, "missing"); !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Collection not found" {
+		t.Errorf("expected a missing collection to be rejected, got: %v", resp.Diagnostics)
+	}
+
+	// Without an index, the project of the API key is unknown.
+	memory.DeleteIndex(ctx, "products")
+	if resp := importTestResource(t, r, "abc123/products-backup"); resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Errorf("expected a warning that the project could not be verified, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIndexResource_ImportState_identity(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestImportMemory()
+	r := NewIndexResource().(*IndexResource)
+	configureTestResource(t, r, newTestProviderData(memory))
+
+	req := fwresource.ImportStateRequest{Identity: newTestIdentity(t, r, &models.ResourceIdentityModel{
+		ProjectId: types.StringNull(),
+		Name:      types.StringValue("products"),
+	})}
+	resp := &fwresource.ImportStateResponse{State: newTestState(testResourceSchema(t, r)), Identity: newTestIdentity(t, r, nil)}
+	r.ImportState(ctx, req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("import failed: %v", resp.Diagnostics)
+	}
+	var imported types.String
+	resp.State.GetAttribute(ctx, path.Root("id"), &imported)
+	if imported.ValueString() != "products" {
+		t.Errorf("expected id products, got %v", imported)
+	}
+	if got := testIdentityValue(t, resp.Identity); got != "abc123/products" {
+		t.Errorf("expected the identity abc123/products, got %s", got)
+	}
+
+	req.Identity = newTestIdentity(t, r, &models.ResourceIdentityModel{
+		ProjectId: types.StringValue("xyz789"),
+		Name:      types.StringValue("products"),
+	})
+	resp = &fwresource.ImportStateResponse{State: newTestState(testResourceSchema(t, r)), Identity: newTestIdentity(t, r, nil)}
+	r.ImportState(ctx, req, resp)
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Wrong Pinecone project" {
+		t.Errorf("expected the wrong project to be rejected, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIndexResource_Read_identity(t *testing.T) {
+	memory := newTestImportMemory()
+	r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+	s := testResourceSchema(t, r)
+	state := newTestState(s)
+	state.SetAttribute(context.Background(), path.Root("id"), "products")
+	state.SetAttribute(context.Background(), path.Root("name"), "products")
+
+	// A resource created before identities were supported gets one on refresh.
+	resp := &fwresource.ReadResponse{State: state, Identity: newTestIdentity(t, r, nil)}
+	r.read(context.Background(), fwresource.ReadRequest{State: state}, resp, testPrivateState{})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("read failed: %v", resp.Diagnostics)
+	}
+	if got := testIdentityValue(t, resp.Identity); got != "abc123/products" {
+		t.Errorf("expected the identity abc123/products, got %s", got)
+	}
+
+	// An identity that differs from the index is updated.
+	resp = &fwresource.ReadResponse{State: state, Identity: newTestIdentity(t, r, &models.ResourceIdentityModel{
+		ProjectId: types.StringValue("xyz789"),
+		Name:      types.StringValue("products"),
+	})}
+	r.read(context.Background(), fwresource.ReadRequest{State: state}, resp, testPrivateState{})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("read failed: %v", resp.Diagnostics)
+	}
+	if got := testIdentityValue(t, resp.Identity); got != "abc123/products" {
+		t.Errorf("expected the identity to be updated to abc123/products, got %s", got)
+	}
+}
+
+func TestCollectionResource_identity(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestImportMemory()
+	r := NewCollectionResource().(*CollectionResource)
+	configureTestResource(t, r, newTestProviderData(memory))
+
+	resp := &fwresource.ImportStateResponse{State: newTestState(testResourceSchema(t, r)), Identity: newTestIdentity(t, r, nil)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "products-backup"}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("import failed: %v", resp.Diagnostics)
+	}
+	// Collections have no host, the project is that of the API key.
+	if got := testIdentityValue(t, resp.Identity); got != "abc123/products-backup" {
+		t.Errorf("expected the identity abc123/products-backup, got %s", got)
+	}
+
+	// Without an index, the project is not known and the identity is left to a later
+	// refresh.
+	memory.DeleteIndex(ctx, "products")
+	resp = &fwresource.ImportStateResponse{State: newTestState(testResourceSchema(t, r)), Identity: newTestIdentity(t, r, nil)}
+	r.ImportState(ctx, fwresource.ImportStateRequest{ID: "products-backup"}, resp)
+	if resp.Diagnostics.HasError() || !resp.Identity.Raw.IsNull() {
+		t.Errorf("expected a null identity, got %v: %v", resp.Identity.Raw, resp.Diagnostics)
+	}
+}
@@ -0,0 +1,624 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+const (
+	defaultIndexCopyCreateTimeout time.Duration = 60 * time.Minute
+	defaultIndexCopyReadTimeout   time.Duration = 5 * time.Minute
+	defaultIndexCopyUpdateTimeout time.Duration = 60 * time.Minute
+
+	defaultIndexCopyBatchSize   int64 = 100
+	defaultIndexCopyConcurrency int64 = 4
+)
+
+// privateKeyExpectedCounts holds, for a copy that has not been verified yet, the number
+// of vectors each copied namespace of the target index should hold at least.
+const privateKeyExpectedCounts = "expected_counts"
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &IndexCopyResource{}
+var _ resource.ResourceWithModifyPlan = &IndexCopyResource{}
+
+func NewIndexCopyResource() resource.Resource {
+	return &IndexCopyResource{PineconeResource: &PineconeResource{}}
+}
+
+// IndexCopyResource copies the vectors of one index into another through their data
+// planes.
+type IndexCopyResource struct {
+	*PineconeResource
+}
+
+func (r *IndexCopyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_index_copy"
+}
+
+func (r *IndexCopyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Copies the vectors of one index into another, existing, index. Vector ids are listed page by page, " +
+			"fetched and upserted into the same namespace of the target index. Unlike collections, this works for serverless " +
+			"indexes and across regions. The source index must be serverless, as only serverless indexes can list their vector " +
+			"ids. A copy interrupted by its timeout saves a checkpoint, and the next apply resumes from " +
+			"it. Destroying the copy only removes it from the state, the copied vectors are kept.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Copy identifier, the names of the source and target indexes.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"source_index": schema.StringAttribute{
+				MarkdownDescription: "The name of the serverless index to copy from. Pod-based indexes cannot list their vector ids, and are rejected.",
+				Required:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"target_index": schema.StringAttribute{
+				MarkdownDescription: "The name of the index to copy into. It must have the same dimension as the source index.",
+				Required:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"namespaces": schema.ListAttribute{
+				MarkdownDescription: "The namespaces to copy. Use an empty string for the default namespace. Defaults to every namespace of the source index.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers:       []planmodifier.List{listplanmodifier.RequiresReplace()},
+			},
+			"exclude_namespaces": schema.ListAttribute{
+				MarkdownDescription: "The namespaces not to copy.",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers:       []planmodifier.List{listplanmodifier.RequiresReplace()},
+			},
+			"id_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only copy the vectors whose id starts with this prefix.",
+				Optional:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"metadata_filter": schema.StringAttribute{
+				MarkdownDescription: "Only copy the vectors whose metadata matches this filter, a JSON object in the metadata filter " +
+					"language of queries. The operators `$eq`, `$ne`, `$gt`, `$gte`, `$lt`, `$lte`, `$in`, `$nin`, `$exists`, `$and` " +
+					"and `$or` are supported. Every listed vector is fetched, the filter is applied by the provider.",
+				Optional:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"batch_size": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of vector ids listed, fetched and upserted at a time. Between 1 and 100, defaults to %d.", defaultIndexCopyBatchSize),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultIndexCopyBatchSize),
+				Validators:          []validator.Int64{int64validator.Between(1, 100)},
+			},
+			"concurrency": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("The number of batches copied at the same time. Between 1 and 32, defaults to %d.", defaultIndexCopyConcurrency),
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultIndexCopyConcurrency),
+				Validators:          []validator.Int64{int64validator.Between(1, 32)},
+			},
+			"complete": schema.BoolAttribute{
+				MarkdownDescription: "Whether every vector has been copied and the copy has been verified. An incomplete copy resumes on the next apply, " +
+					"and a copy that could not be verified is verified again.",
+				Computed:      true,
+				PlanModifiers: []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+			},
+			"checkpoint": schema.SingleNestedAttribute{
+				MarkdownDescription: "Where an interrupted copy resumes. Null once every vector has been copied.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Object{objectplanmodifier.UseStateForUnknown()},
+				Attributes: map[string]schema.Attribute{
+					"namespace": schema.StringAttribute{
+						MarkdownDescription: "The namespace being copied. Namespaces are copied in lexical order.",
+						Computed:            true,
+					},
+					"pagination_token": schema.StringAttribute{
+						MarkdownDescription: "The token of the next page of vector ids in the namespace, or null to start from the first page.",
+						Computed:            true,
+					},
+				},
+			},
+			"source_vector_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of vectors in the copied namespaces of the source index when the copy started, before id_prefix and metadata_filter are applied.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"copied_vector_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of vectors upserted into the target index.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"target_vector_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of vectors in the copied namespaces of the target index when the copy was verified. Every namespace " +
+					"holds at least the vectors it held when the copy started, plus the copied vectors whose ids it did not hold yet. " +
+					"The counts come from the index stats, which are eventually consistent, so the check is a lower bound: the count " +
+					"taken when the copy started may miss vectors written just before.",
+				Computed:      true,
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx,
+				timeouts.Opts{
+					Create:            true,
+					CreateDescription: timeoutDescription(defaultIndexCopyCreateTimeout),
+					Read:              true,
+					ReadDescription:   timeoutDescription(defaultIndexCopyReadTimeout),
+					Update:            true,
+					UpdateDescription: timeoutDescription(defaultIndexCopyUpdateTimeout),
+				},
+			),
+		},
+	}
+	addAttributes(resp.Schema.Attributes, credentialResourceAttributes("copy", r.PineconeResource))
+}
+
+func (r *IndexCopyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.denyReadOnlyPlan(req, resp, "index copy")
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data models.IndexCopyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if isKnown(data.MetadataFilter) {
+		if _, err := parseMetadataFilter(data.MetadataFilter.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("metadata_filter"), "Invalid metadata filter", err.Error())
+			return
+		}
+	}
+
+	if req.State.Raw.IsNull() {
+		if isKnown(data.SourceIndex) {
+			r.checkSourceIndex(ctx, &data, resp)
+		}
+		return
+	}
+	var prior models.IndexCopyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !prior.Complete.ValueBool() {
+		// Plan an update, which resumes the copy.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("complete"), types.BoolUnknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("checkpoint"), types.ObjectUnknown(models.IndexCopyCheckpointModel{}.AttrTypes()))...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("copied_vector_count"), types.Int64Unknown())...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("target_vector_count"), types.Int64Unknown())...)
+	}
+}
+
+// checkSourceIndex rejects a source index that cannot list its vector ids. A source
+// index that does not exist yet is checked when the copy starts.
+func (r *IndexCopyResource) checkSourceIndex(ctx context.Context, data *models.IndexCopyResourceModel, resp *resource.ModifyPlanResponse) {
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	source, err := api.DescribeIndex(ctx, data.SourceIndex.ValueString())
+	if err != nil {
+		if !isNotFoundError(err) {
+			resp.Diagnostics.AddAttributeError(path.Root("source_index"), "Failed to describe source index", err.Error())
+		}
+		return
+	}
+	resp.Diagnostics.Append(copySourceDiagnostics(source)...)
+}
+
+// copySourceDiagnostics returns an error when the vector ids of source cannot be
+// listed, which only serverless indexes support.
+func copySourceDiagnostics(source *pinecone.Index) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if source.Spec == nil || source.Spec.Serverless == nil {
+		diags.AddAttributeError(path.Root("source_index"), "Unsupported source index",
+			fmt.Sprintf("Index %s is not serverless. Only serverless indexes can list their vector ids, which copying "+
+				"vectors requires. Copy a pod-based index through a collection instead.", source.Name))
+	}
+	return diags
+}
+
+func (r *IndexCopyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	r.create(ctx, req, resp, resp.Private)
+}
+
+// create is Create with the private state of the response.
+func (r *IndexCopyResource) create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, private privateState) {
+	resp.Diagnostics.Append(r.denyReadOnly("create", "index copy")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data models.IndexCopyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, timeoutOrDefault(r.timeouts.Create, defaultIndexCopyCreateTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(data.SourceIndex.ValueString() + ":" + data.TargetIndex.ValueString())
+	data.SourceVectorCount = types.Int64Unknown()
+	data.CopiedVectorCount = types.Int64Value(0)
+	data.Checkpoint = types.ObjectUnknown(models.IndexCopyCheckpointModel{}.AttrTypes())
+	resp.Diagnostics.Append(r.copyVectors(ctx, api, &data, createTimeout, &resp.State, private)...)
+}
+
+func (r *IndexCopyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.IndexCopyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, timeoutOrDefault(r.timeouts.Read, defaultIndexCopyReadTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	if _, err := api.DescribeIndex(ctx, data.TargetIndex.ValueString()); err != nil {
+		if isNotFoundError(err) {
+			// The copied vectors are gone with the target index.
+			resp.State.RemoveResource(ctx)
+		} else {
+			resp.Diagnostics.AddError("Failed to describe target index", err.Error())
+		}
+	}
+}
+
+func (r *IndexCopyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	r.update(ctx, req, resp, resp.Private)
+}
+
+// update is Update with the private state of the response.
+func (r *IndexCopyResource) update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse, private privateState) {
+	resp.Diagnostics.Append(r.denyReadOnly("update", "index copy")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data, prior models.IndexCopyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if prior.Complete.ValueBool() {
+		// Only settings such as batch_size and timeouts changed.
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, timeoutOrDefault(r.timeouts.Update, defaultIndexCopyUpdateTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Checkpoint = prior.Checkpoint
+	data.CopiedVectorCount = prior.CopiedVectorCount
+	resp.Diagnostics.Append(r.copyVectors(ctx, api, &data, updateTimeout, &resp.State, private)...)
+}
+
+func (r *IndexCopyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("delete", "index copy")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The copied vectors are kept, and the framework removes the copy from state.
+	var data models.IndexCopyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("forgetting copy of index %s into %s, the copied vectors are kept", data.SourceIndex.ValueString(), data.TargetIndex.ValueString()))
+}
+
+// copyVectors copies the vectors described by data, from its checkpoint on, and then
+// verifies the copy. The progress is saved into state after every round of pages, so
+// that a copy interrupted by the timeout resumes on the next apply. An unknown
+// checkpoint starts a new copy. A copy that could not be verified keeps the counts it
+// expects in private, and the next apply verifies it again.
+func (r *IndexCopyResource) copyVectors(ctx context.Context, api client.ControlPlane, data *models.IndexCopyResourceModel, timeout time.Duration, state *tfsdk.State, private privateState) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	data.Complete = types.BoolValue(false)
+	data.TargetVectorCount = types.Int64Null()
+
+	source, err := api.DescribeIndex(ctx, data.SourceIndex.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("source_index"), "Failed to describe source index", err.Error())
+		return diags
+	}
+	target, err := api.DescribeIndex(ctx, data.TargetIndex.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("target_index"), "Failed to describe target index", err.Error())
+		return diags
+	}
+	if diags.Append(copySourceDiagnostics(source)...); diags.HasError() {
+		return diags
+	}
+	if source.Dimension != target.Dimension {
+		diags.AddAttributeError(path.Root("target_index"), "Dimension mismatch",
+			fmt.Sprintf("Index %s has dimension %d, but source index %s has dimension %d.", target.Name, target.Dimension, source.Name, source.Dimension))
+		return diags
+	}
+
+	copier := &vectorCopy{
+		BatchSize:   uint32(data.BatchSize.ValueInt64()),
+		Concurrency: int(data.Concurrency.ValueInt64()),
+	}
+	if isKnown(data.IdPrefix) {
+		copier.Prefix = data.IdPrefix.ValueStringPointer()
+	}
+	if isKnown(data.MetadataFilter) {
+		copier.Match, err = parseMetadataFilter(data.MetadataFilter.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("metadata_filter"), "Invalid metadata filter", err.Error())
+			return diags
+		}
+	}
+	if copier.Source, err = api.Index(ctx, source.Host); err != nil {
+		diags.AddError("Failed to connect to source index", err.Error())
+		return diags
+	}
+	defer copier.Source.Close()
+	if copier.Target, err = api.Index(ctx, target.Host); err != nil {
+		diags.AddError("Failed to connect to target index", err.Error())
+		return diags
+	}
+	defer copier.Target.Close()
+
+	stats, err := copier.Source.DescribeIndexStats(ctx)
+	if err != nil {
+		diags.AddError("Failed to describe source index stats", err.Error())
+		return diags
+	}
+	var include, exclude []string
+	diags.Append(data.Namespaces.ElementsAs(ctx, &include, false)...)
+	diags.Append(data.ExcludeNamespaces.ElementsAs(ctx, &exclude, false)...)
+	if diags.HasError() {
+		return diags
+	}
+	copier.Namespaces = copyNamespaces(stats, include, exclude)
+	if data.SourceVectorCount.IsUnknown() {
+		var count int64
+		for _, namespace := range copier.Namespaces {
+			if summary := stats.Namespaces[namespace]; summary != nil {
+				count += int64(summary.VectorCount)
+			}
+		}
+		data.SourceVectorCount = types.Int64Value(count)
+	}
+
+	// The counts the target index is verified against: the vectors each namespace held
+	// when the copy started, plus the copied vectors it did not hold. They are kept in
+	// private with the checkpoint, so that a resumed copy does not take its baseline
+	// from stats that may not count the vectors upserted just before the interruption.
+	expected := map[string]int64{}
+	if !data.Checkpoint.IsUnknown() {
+		value, d := private.GetKey(ctx, privateKeyExpectedCounts)
+		diags.Append(d...)
+		if len(value) > 0 {
+			if err := json.Unmarshal(value, &expected); err != nil {
+				diags.AddError("Failed to read expected vector counts", err.Error())
+			}
+		}
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	var checkpoint *copyCheckpoint
+	if data.Checkpoint.IsUnknown() {
+		// A new copy. Record its start, so that a copy that was cut short is not
+		// mistaken for a finished one.
+		before, err := copier.Target.DescribeIndexStats(ctx)
+		if err != nil {
+			diags.AddError("Failed to describe target index stats", err.Error())
+			return diags
+		}
+		for _, namespace := range copier.Namespaces {
+			if summary := before.Namespaces[namespace]; summary != nil {
+				expected[namespace] = int64(summary.VectorCount)
+			}
+		}
+		if len(copier.Namespaces) > 0 {
+			checkpoint = &copyCheckpoint{Namespace: copier.Namespaces[0]}
+		}
+		data.Checkpoint = copyCheckpointValue(checkpoint)
+		diags.Append(setExpectedCounts(ctx, private, expected)...)
+		diags.Append(state.Set(ctx, data)...)
+	} else if !data.Checkpoint.IsNull() {
+		var model models.IndexCopyCheckpointModel
+		diags.Append(data.Checkpoint.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+		checkpoint = &copyCheckpoint{Namespace: model.Namespace.ValueString(), PaginationToken: model.PaginationToken.ValueStringPointer()}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	if checkpoint != nil {
+		base := maps.Clone(expected)
+		progress := func() {
+			for namespace, added := range copier.Added {
+				expected[namespace] = base[namespace] + added
+			}
+			diags.Append(setExpectedCounts(ctx, private, expected)...)
+		}
+		err = copier.Run(ctx, checkpoint, func(checkpoint *copyCheckpoint, copied int64) {
+			progress()
+			data.Checkpoint = copyCheckpointValue(checkpoint)
+			data.CopiedVectorCount = types.Int64Value(data.CopiedVectorCount.ValueInt64() + copied)
+			diags.Append(state.Set(ctx, data)...)
+		})
+		if err != nil {
+			// The vectors upserted before the error are counted, as the pages that are
+			// copied again find them in the target.
+			progress()
+			diags.Append(state.Set(ctx, data)...)
+			if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) {
+				diags.AddWarning("Copy interrupted",
+					fmt.Sprintf("%d vectors have been copied into index %s before the timeout. The next apply resumes the copy: %s",
+						data.CopiedVectorCount.ValueInt64(), target.Name, err))
+			} else {
+				diags.AddError("Failed to copy vectors", err.Error())
+			}
+			return diags
+		}
+	}
+
+	// Verify the copy: every namespace of the target index holds the vectors it held
+	// when the copy started, and the copied vectors it did not hold. The stats of serverless indexes
+	// report upserted vectors with a delay, so they are polled until they are counted.
+	var want int64
+	for _, count := range expected {
+		want += count
+	}
+	w := r.newWaiter("index", target.Name, timeout, func(ctx context.Context) (string, waiter.Class, error) {
+		stats, err := copier.Target.DescribeIndexStats(ctx)
+		if err != nil {
+			return "", waiter.Pending, err
+		}
+		var count int64
+		verified := true
+		for _, namespace := range copier.Namespaces {
+			var n int64
+			if summary := stats.Namespaces[namespace]; summary != nil {
+				n = int64(summary.VectorCount)
+			}
+			count += n
+			verified = verified && n >= expected[namespace]
+		}
+		data.TargetVectorCount = types.Int64Value(count)
+		if verified {
+			return fmt.Sprintf("%d vectors", count), waiter.Target, nil
+		}
+		return fmt.Sprintf("%d of %d vectors", count, want), waiter.Pending, nil
+	})
+	if _, err := w.Wait(ctx); err != nil {
+		// The vectors have been copied, so the copy is kept and verified again on the
+		// next apply rather than tainted.
+		diags.Append(state.Set(ctx, data)...)
+		diags.AddAttributeWarning(path.Root("target_vector_count"), "Copy not verified",
+			fmt.Sprintf("The copied namespaces of index %s should hold at least %d vectors, but hold %s: %s. "+
+				"The next apply verifies the copy again.", target.Name, want, data.TargetVectorCount, err))
+		return diags
+	}
+
+	data.Complete = types.BoolValue(true)
+	diags.Append(private.SetKey(ctx, privateKeyExpectedCounts, nil)...)
+	diags.Append(state.Set(ctx, data)...)
+	return diags
+}
+
+// setExpectedCounts saves the counts a copy is verified against into private.
+func setExpectedCounts(ctx context.Context, private privateState, expected map[string]int64) diag.Diagnostics {
+	var diags diag.Diagnostics
+	value, err := json.Marshal(expected)
+	if err != nil {
+		diags.AddError("Failed to save expected vector counts", err.Error())
+		return diags
+	}
+	diags.Append(private.SetKey(ctx, privateKeyExpectedCounts, value)...)
+	return diags
+}
+
+// copyNamespaces returns the namespaces to copy in lexical order: include, or every
+// namespace in stats when include is empty, without exclude.
+func copyNamespaces(stats *pinecone.DescribeIndexStatsResponse, include []string, exclude []string) []string {
+	if len(include) == 0 {
+		for namespace := range stats.Namespaces {
+			include = append(include, namespace)
+		}
+	}
+	excluded := map[string]bool{}
+	for _, namespace := range exclude {
+		excluded[namespace] = true
+	}
+
+	var namespaces []string
+	for _, namespace := range include {
+		if !excluded[namespace] {
+			namespaces = append(namespaces, namespace)
+			excluded[namespace] = true
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+func copyCheckpointValue(checkpoint *copyCheckpoint) types.Object {
+	if checkpoint == nil {
+		return types.ObjectNull(models.IndexCopyCheckpointModel{}.AttrTypes())
+	}
+	return types.ObjectValueMust(models.IndexCopyCheckpointModel{}.AttrTypes(), map[string]attr.Value{
+		"namespace":        types.StringValue(checkpoint.Namespace),
+		"pagination_token": types.StringPointerValue(checkpoint.PaginationToken),
+	})
+}
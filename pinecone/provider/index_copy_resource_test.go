@@ -0,0 +1,364 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// newTestCopyMemory returns a Memory holding the indexes "prod" and "staging". prod
+// holds five vectors in the default namespace, three in "a" and two in "skip".
+func newTestCopyMemory(t *testing.T) *client.Memory {
+	t.Helper()
+
+	memory := client.NewMemory()
+	for _, name := range []string{"prod", "staging"} {
+		memory.PutIndex(&pinecone.Index{
+			Name:      name,
+			Dimension: 4,
+			Host:      name + "-memory.svc.pinecone.io",
+			Spec:      &pinecone.IndexSpec{Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-east-1"}},
+			Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+		})
+	}
+	for i := 0; i < 5; i++ {
+		memory.PutVectors("prod-memory.svc.pinecone.io", "", &pinecone.Vector{Id: fmt.Sprintf("doc-%d", i), Values: []float32{1, 2, 3, 4}})
+	}
+	for i, genre := range []string{"comedy", "drama", "comedy"} {
+		metadata, err := structpb.NewStruct(map[string]interface{}{"genre": genre})
+		if err != nil {
+			t.Fatal(err)
+		}
+		memory.PutVectors("prod-memory.svc.pinecone.io", "a", &pinecone.Vector{Id: fmt.Sprintf("movie-%d", i), Metadata: metadata})
+	}
+	memory.PutVectors("prod-memory.svc.pinecone.io", "skip", &pinecone.Vector{Id: "x"}, &pinecone.Vector{Id: "y"})
+	return memory
+}
+
+func testIndexCopyPlan() models.IndexCopyResourceModel {
+	return models.IndexCopyResourceModel{
+		Id:                types.StringUnknown(),
+		SourceIndex:       types.StringValue("prod"),
+		TargetIndex:       types.StringValue("staging"),
+		Namespaces:        types.ListNull(types.StringType),
+		ExcludeNamespaces: types.ListNull(types.StringType),
+		IdPrefix:          types.StringNull(),
+		MetadataFilter:    types.StringNull(),
+		BatchSize:         types.Int64Value(2),
+		Concurrency:       types.Int64Value(2),
+		Complete:          types.BoolUnknown(),
+		Checkpoint:        types.ObjectUnknown(models.IndexCopyCheckpointModel{}.AttrTypes()),
+		SourceVectorCount: types.Int64Unknown(),
+		CopiedVectorCount: types.Int64Unknown(),
+		TargetVectorCount: types.Int64Unknown(),
+		Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+			"update": types.StringType,
+		})},
+	}
+}
+
+// createTestCopy runs create with private for plan and returns the response.
+func createTestCopy(t *testing.T, r *IndexCopyResource, plan models.IndexCopyResourceModel, private privateState) *fwresource.CreateResponse {
+	t.Helper()
+
+	s := testResourceSchema(t, r)
+	resp := &fwresource.CreateResponse{State: newTestState(s)}
+	r.create(context.Background(), fwresource.CreateRequest{Plan: newTestPlan(t, s, plan)}, resp, private)
+	return resp
+}
+
+// resumeTestCopy plans and runs the update of an incomplete copy, and returns the
+// update response.
+func resumeTestCopy(t *testing.T, r *IndexCopyResource, state tfsdk.State, private privateState) *fwresource.UpdateResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	modifyResp := &fwresource.ModifyPlanResponse{Plan: tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: modifyResp.Plan, State: state}, modifyResp)
+	if modifyResp.Diagnostics.HasError() {
+		t.Fatalf("plan failed: %v", modifyResp.Diagnostics)
+	}
+	var planned models.IndexCopyResourceModel
+	modifyResp.Plan.Get(ctx, &planned)
+	if !planned.Complete.IsUnknown() {
+		t.Fatalf("expected an incomplete copy to plan an update, got complete %v", planned.Complete)
+	}
+
+	updateResp := &fwresource.UpdateResponse{State: state}
+	r.update(ctx, fwresource.UpdateRequest{Plan: modifyResp.Plan, State: state}, updateResp, private)
+	return updateResp
+}
+
+// testNamespaceIds returns the ids stored in a namespace of the index served at host.
+func testNamespaceIds(t *testing.T, memory *client.Memory, host string, namespace string) []string {
+	t.Helper()
+
+	index, _ := memory.Index(context.Background(), host)
+	resp, err := index.ListVectors(context.Background(), namespace, &pinecone.ListVectorsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, id := range resp.VectorIds {
+		ids = append(ids, *id)
+	}
+	return ids
+}
+
+func TestIndexCopyResource_lifecycle(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestCopyMemory(t)
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, newTestProviderData(memory))
+
+	plan := testIndexCopyPlan()
+	plan.ExcludeNamespaces = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("skip")})
+	created := createTestCopy(t, r, plan, testPrivateState{})
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+
+	var state models.IndexCopyResourceModel
+	created.State.Get(ctx, &state)
+	if !state.Complete.ValueBool() || !state.Checkpoint.IsNull() {
+		t.Errorf("expected a complete copy, got complete %v and checkpoint %v", state.Complete, state.Checkpoint)
+	}
+	if state.SourceVectorCount.ValueInt64() != 8 || state.CopiedVectorCount.ValueInt64() != 8 || state.TargetVectorCount.ValueInt64() != 8 {
+		t.Errorf("unexpected vector counts %v, %v and %v", state.SourceVectorCount, state.CopiedVectorCount, state.TargetVectorCount)
+	}
+	if state.Id.ValueString() != "prod:staging" {
+		t.Errorf("unexpected id %v", state.Id)
+	}
+	if ids := testNamespaceIds(t, memory, "staging-memory.svc.pinecone.io", ""); len(ids) != 5 {
+		t.Errorf("expected the default namespace to be copied, got %v", ids)
+	}
+	if ids := testNamespaceIds(t, memory, "staging-memory.svc.pinecone.io", "skip"); len(ids) != 0 {
+		t.Errorf("expected the excluded namespace not to be copied, got %v", ids)
+	}
+
+	// Destroying the copy keeps the copied vectors.
+	deleteResp := &fwresource.DeleteResponse{State: created.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: created.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("delete failed: %v", deleteResp.Diagnostics)
+	}
+	if ids := testNamespaceIds(t, memory, "staging-memory.svc.pinecone.io", "a"); len(ids) != 3 {
+		t.Errorf("expected the copied vectors to be kept, got %v", ids)
+	}
+
+	// A target deleted out of band removes the copy from state.
+	memory.DeleteIndex(ctx, "staging")
+	readResp := &fwresource.ReadResponse{State: created.State}
+	r.Read(ctx, fwresource.ReadRequest{State: created.State}, readResp)
+	if readResp.Diagnostics.HasError() || !readResp.State.Raw.IsNull() {
+		t.Errorf("expected the copy to be removed from state, got: %v", readResp.Diagnostics)
+	}
+}
+
+func TestIndexCopyResource_filters(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestCopyMemory(t)
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, newTestProviderData(memory))
+
+	plan := testIndexCopyPlan()
+	plan.Namespaces = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a")})
+	plan.IdPrefix = types.StringValue("movie-")
+	plan.MetadataFilter = types.StringValue(`{"genre": "comedy"}`)
+	created := createTestCopy(t, r, plan, testPrivateState{})
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+
+	var state models.IndexCopyResourceModel
+	created.State.Get(ctx, &state)
+	if state.SourceVectorCount.ValueInt64() != 3 || state.CopiedVectorCount.ValueInt64() != 2 {
+		t.Errorf("unexpected vector counts %v and %v", state.SourceVectorCount, state.CopiedVectorCount)
+	}
+	if ids := testNamespaceIds(t, memory, "staging-memory.svc.pinecone.io", "a"); fmt.Sprint(ids) != "[movie-0 movie-2]" {
+		t.Errorf("expected the comedies to be copied, got %v", ids)
+	}
+	if ids := testNamespaceIds(t, memory, "staging-memory.svc.pinecone.io", ""); len(ids) != 0 {
+		t.Errorf("expected only the included namespace to be copied, got %v", ids)
+	}
+}
+
+func TestIndexCopyResource_resume(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestCopyMemory(t)
+	// The second upsert runs into the timeout.
+	memory.Hook = func(op client.Operation, name string) error {
+		if op == client.OpUpsertVectors && memory.Calls(client.OpUpsertVectors) == 2 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	}
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, newTestProviderData(memory))
+	private := testPrivateState{}
+
+	plan := testIndexCopyPlan()
+	plan.Namespaces = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("")})
+	plan.Concurrency = types.Int64Value(1)
+	created := createTestCopy(t, r, plan, private)
+	if created.Diagnostics.HasError() || created.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected the copy to be interrupted, got: %v", created.Diagnostics)
+	}
+	var state models.IndexCopyResourceModel
+	created.State.Get(ctx, &state)
+	var checkpoint models.IndexCopyCheckpointModel
+	state.Checkpoint.As(ctx, &checkpoint, basetypes.ObjectAsOptions{})
+	if state.Complete.ValueBool() || state.CopiedVectorCount.ValueInt64() != 2 || checkpoint.PaginationToken.ValueString() != "doc-1" {
+		t.Fatalf("expected a checkpoint after the first batch, got %v, %v and %v", state.Complete, state.CopiedVectorCount, state.Checkpoint)
+	}
+	// The counts to verify are kept with the checkpoint rather than taken again.
+	if string(private[privateKeyExpectedCounts]) != `{"":2}` {
+		t.Errorf("expected the copied batch to be expected, got %s", private[privateKeyExpectedCounts])
+	}
+
+	// The next plan resumes the copy.
+	updateResp := resumeTestCopy(t, r, created.State, private)
+	if updateResp.Diagnostics.HasError() {
+		t.Fatalf("update failed: %v", updateResp.Diagnostics)
+	}
+	updateResp.State.Get(ctx, &state)
+	if !state.Complete.ValueBool() || state.CopiedVectorCount.ValueInt64() != 5 || state.TargetVectorCount.ValueInt64() != 5 {
+		t.Errorf("expected the copy to complete, got %v, %v and %v", state.Complete, state.CopiedVectorCount, state.TargetVectorCount)
+	}
+	// The first batch is not copied again: two batches before the interruption, two after.
+	if n := memory.Calls(client.OpUpsertVectors); n != 4 {
+		t.Errorf("expected 4 upserts, got %d", n)
+	}
+	if len(private) != 0 {
+		t.Errorf("expected the expected counts to be cleared, got %v", private)
+	}
+}
+
+func TestIndexCopyResource_dimensionMismatch(t *testing.T) {
+	memory := newTestCopyMemory(t)
+	memory.PutIndex(&pinecone.Index{Name: "staging", Dimension: 8, Host: "staging-memory.svc.pinecone.io"})
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, newTestProviderData(memory))
+
+	created := createTestCopy(t, r, testIndexCopyPlan(), testPrivateState{})
+	if !created.Diagnostics.HasError() || created.Diagnostics.Errors()[0].Summary() != "Dimension mismatch" {
+		t.Fatalf("expected a dimension mismatch, got: %v", created.Diagnostics)
+	}
+	if memory.Calls(client.OpUpsertVectors) != 0 {
+		t.Error("expected no vectors to be copied")
+	}
+}
+
+func TestIndexCopyResource_overwrite(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestCopyMemory(t)
+	// The target already holds two of the copied ids, and a vector of its own.
+	memory.PutVectors("staging-memory.svc.pinecone.io", "", &pinecone.Vector{Id: "doc-0"}, &pinecone.Vector{Id: "doc-1"}, &pinecone.Vector{Id: "own"})
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, newTestProviderData(memory))
+
+	plan := testIndexCopyPlan()
+	plan.Namespaces = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("")})
+	created := createTestCopy(t, r, plan, testPrivateState{})
+	if created.Diagnostics.HasError() || created.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("expected the overwriting copy to be verified, got: %v", created.Diagnostics)
+	}
+	var state models.IndexCopyResourceModel
+	created.State.Get(ctx, &state)
+	if !state.Complete.ValueBool() || state.CopiedVectorCount.ValueInt64() != 5 || state.TargetVectorCount.ValueInt64() != 6 {
+		t.Errorf("unexpected copy %v, %v and %v", state.Complete, state.CopiedVectorCount, state.TargetVectorCount)
+	}
+}
+
+func TestIndexCopyResource_notVerified(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestCopyMemory(t)
+	// The target already holds more vectors than are copied, and loses a copied one
+	// before it is verified.
+	for i := 0; i < 6; i++ {
+		memory.PutVectors("staging-memory.svc.pinecone.io", "", &pinecone.Vector{Id: fmt.Sprintf("own-%d", i)})
+	}
+	memory.Hook = func(op client.Operation, name string) error {
+		if op == client.OpDescribeIndexStats && name == "staging-memory.svc.pinecone.io" {
+			memory.DeleteVectors(name, "", "doc-4")
+		}
+		return nil
+	}
+	data := newTestProviderData(memory)
+	data.Timeouts = DefaultTimeouts{Create: 50 * time.Millisecond}
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, data)
+
+	private := testPrivateState{}
+	plan := testIndexCopyPlan()
+	plan.Namespaces = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("")})
+	created := createTestCopy(t, r, plan, private)
+	if created.Diagnostics.HasError() || created.Diagnostics.WarningsCount() != 1 || created.Diagnostics.Warnings()[0].Summary() != "Copy not verified" {
+		t.Fatalf("expected the verification to warn, got: %v", created.Diagnostics)
+	}
+	var state models.IndexCopyResourceModel
+	created.State.Get(ctx, &state)
+	if state.Complete.ValueBool() || state.CopiedVectorCount.ValueInt64() != 5 || state.TargetVectorCount.ValueInt64() != 10 {
+		t.Fatalf("expected an unverified copy, got %v, %v and %v", state.Complete, state.CopiedVectorCount, state.TargetVectorCount)
+	}
+	if string(private[privateKeyExpectedCounts]) != `{"":11}` {
+		t.Errorf("expected the expected counts to be kept, got %s", private[privateKeyExpectedCounts])
+	}
+
+	// Once the vector shows up, the next apply verifies the copy without copying again.
+	memory.Hook = nil
+	memory.PutVectors("staging-memory.svc.pinecone.io", "", &pinecone.Vector{Id: "doc-4"})
+	upserts := memory.Calls(client.OpUpsertVectors)
+	updateResp := resumeTestCopy(t, r, created.State, private)
+	if updateResp.Diagnostics.HasError() || updateResp.Diagnostics.WarningsCount() != 0 {
+		t.Fatalf("expected the copy to be verified, got: %v", updateResp.Diagnostics)
+	}
+	updateResp.State.Get(ctx, &state)
+	if !state.Complete.ValueBool() || state.TargetVectorCount.ValueInt64() != 11 {
+		t.Errorf("expected a verified copy, got %v and %v", state.Complete, state.TargetVectorCount)
+	}
+	if memory.Calls(client.OpUpsertVectors) != upserts || len(private) != 0 {
+		t.Errorf("expected no vectors to be copied again and the expected counts to be cleared, got %v", private)
+	}
+}
+
+func TestIndexCopyResource_podSource(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestCopyMemory(t)
+	memory.PutIndex(&pinecone.Index{
+		Name:      "prod",
+		Dimension: 4,
+		Host:      "prod-memory.svc.pinecone.io",
+		Spec:      &pinecone.IndexSpec{Pod: &pinecone.PodSpec{Environment: "us-east1-gcp", PodType: "p1.x1"}},
+		Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	})
+	r := newTestResource[*IndexCopyResource](t, NewIndexCopyResource, newTestProviderData(memory))
+	s := testResourceSchema(t, r)
+
+	plan := newTestPlan(t, s, testIndexCopyPlan())
+	modifyResp := &fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: newTestState(s)}, modifyResp)
+	if !modifyResp.Diagnostics.HasError() || modifyResp.Diagnostics.Errors()[0].Summary() != "Unsupported source index" {
+		t.Fatalf("expected the pod-based source to be rejected, got: %v", modifyResp.Diagnostics)
+	}
+
+	// A source that did not exist at plan time is checked when the copy starts.
+	created := createTestCopy(t, r, testIndexCopyPlan(), testPrivateState{})
+	if !created.Diagnostics.HasError() || created.Diagnostics.Errors()[0].Summary() != "Unsupported source index" {
+		t.Fatalf("expected the pod-based source to be rejected, got: %v", created.Diagnostics)
+	}
+	if memory.Calls(client.OpListVectors) != 0 {
+		t.Error("expected no vectors to be listed")
+	}
+}
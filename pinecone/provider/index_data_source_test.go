@@ -4,11 +4,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
 func TestAccIndexDataSource_serverless(t *testing.T) {
@@ -34,6 +39,70 @@ func TestAccIndexDataSource_serverless(t *testing.T) {
 	})
 }
 
+func TestIndexDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	memory.PutIndex(testIndexes()["pod"])
+	d := NewIndexDataSource().(*IndexDataSource)
+	s := configureTestDataSource(t, d, newTestProviderData(memory))
+
+	for name, wantErr := range map[string]bool{"pod-index": false, "missing": true} {
+		config := models.IndexDatasourceModel{
+			Id:        types.StringNull(),
+			Name:      types.StringValue(name),
+			Dimension: types.Int64Null(),
+			Metric:    types.StringNull(),
+			Host:      types.StringNull(),
+			Spec:      types.ObjectNull(models.IndexSpecModel{}.AttrTypes()),
+			Status:    types.ObjectNull(models.IndexStatusModel{}.AttrTypes()),
+		}
+		resp := &datasource.ReadResponse{State: newTestDataSourceState(s)}
+		d.Read(ctx, datasource.ReadRequest{Config: newTestConfig(t, s, &config)}, resp)
+		if resp.Diagnostics.HasError() != wantErr {
+			t.Errorf("%s: expected error %t, got: %v", name, wantErr, resp.Diagnostics)
+			continue
+		}
+		if wantErr {
+			continue
+		}
+
+		var state models.IndexDatasourceModel
+		if diags := resp.State.Get(ctx, &state); diags.HasError() {
+			t.Fatalf("%s: invalid state: %v", name, diags)
+		}
+		if state.Id.ValueString() != name || state.Dimension.ValueInt64() != 1536 || state.Host.IsNull() {
+			t.Errorf("%s: unexpected state: %+v", name, state)
+		}
+	}
+}
+
+func TestIndexDataSource_Read_projectApiKey(t *testing.T) {
+	ctx := context.Background()
+	projects := map[string]*client.Memory{"prod-key": client.NewMemory()}
+	projects["prod-key"].PutIndex(testIndexes()["pod"])
+	data := newTestProviderData(client.NewMemory())
+	data.Clients = newTestClientCache(projects)
+	d := NewIndexDataSource().(*IndexDataSource)
+	s := configureTestDataSource(t, d, data)
+
+	config := models.IndexDatasourceModel{
+		Id:            types.StringNull(),
+		Name:          types.StringValue("pod-index"),
+		Dimension:     types.Int64Null(),
+		Metric:        types.StringNull(),
+		Host:          types.StringNull(),
+		Spec:          types.ObjectNull(models.IndexSpecModel{}.AttrTypes()),
+		Status:        types.ObjectNull(models.IndexStatusModel{}.AttrTypes()),
+		ProjectApiKey: types.StringValue("prod-key"),
+		Profile:       types.StringNull(),
+	}
+	resp := &datasource.ReadResponse{State: newTestDataSourceState(s)}
+	d.Read(ctx, datasource.ReadRequest{Config: newTestConfig(t, s, &config)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected the index to be found in the project of project_api_key: %v", resp.Diagnostics)
+	}
+}
+
 func testAccIndexDataSourceConfig_serverless(name string) string {
 	return fmt.Sprintf(`
 	provider "pinecone" {
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// indexHost holds the parts of an index host such as
+// "docs-example-4zo0ijk.svc.us-west1-gcp.pinecone.io".
+type indexHost struct {
+	Name      string
+	ProjectId string
+	Region    string
+}
+
+// parseIndexHost splits an index host into its parts. A URL scheme, port or path
+// around the host is ignored.
+func parseIndexHost(host string) (indexHost, error) {
+	h := host
+	if _, rest, ok := strings.Cut(h, "://"); ok {
+		h = rest
+	}
+	if i := strings.IndexAny(h, ":/"); i >= 0 {
+		h = h[:i]
+	}
+
+	label, rest, ok := strings.Cut(h, ".svc.")
+	region, isPinecone := strings.CutSuffix(rest, ".pinecone.io")
+	i := strings.LastIndex(label, "-")
+	if !ok || !isPinecone || region == "" || i <= 0 || i == len(label)-1 {
+		return indexHost{}, fmt.Errorf("%q is not an index host. Index hosts look like <name>-<project id>.svc.<region>.pinecone.io", host)
+	}
+	return indexHost{Name: label[:i], ProjectId: label[i+1:], Region: region}, nil
+}
+
+var _ function.Function = &IndexHostPartsFunction{}
+
+func NewIndexHostPartsFunction() function.Function {
+	return &IndexHostPartsFunction{}
+}
+
+// IndexHostPartsFunction splits an index host into the index name, project ID and
+// region.
+type IndexHostPartsFunction struct{}
+
+var indexHostPartsAttrTypes = map[string]attr.Type{
+	"name":       types.StringType,
+	"project_id": types.StringType,
+	"region":     types.StringType,
+}
+
+func (f *IndexHostPartsFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "index_host_parts"
+}
+
+func (f *IndexHostPartsFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Split an index host into its parts",
+		MarkdownDescription: "Splits the host of an index, such as `docs-example-4zo0ijk.svc.us-west1-gcp.pinecone.io`, into an object " +
+			"with the index `name`, the `project_id` and the `region`. The region is the environment of pod indexes, and an " +
+			"opaque identifier for most serverless indexes. A URL such as `https://<host>/query` is accepted as well.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "host",
+				MarkdownDescription: "The host of the index, the `host` attribute of `pinecone_index`.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: indexHostPartsAttrTypes},
+	}
+}
+
+func (f *IndexHostPartsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var host string
+	resp.Error = req.Arguments.Get(ctx, &host)
+	if resp.Error != nil {
+		return
+	}
+
+	parts, err := parseIndexHost(host)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(indexHostPartsAttrTypes, map[string]attr.Value{
+		"name":       types.StringValue(parts.Name),
+		"project_id": types.StringValue(parts.ProjectId),
+		"region":     types.StringValue(parts.Region),
+	}))
+}
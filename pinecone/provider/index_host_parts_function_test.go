@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParseIndexHost(t *testing.T) {
+	cases := []struct {
+		host string
+		want indexHost
+	}{
+		{"docs-example-4zo0ijk.svc.us-west1-gcp.pinecone.io", indexHost{"docs-example", "4zo0ijk", "us-west1-gcp"}},
+		{"example-govk0nt.svc.aped-4627-b74a.pinecone.io", indexHost{"example", "govk0nt", "aped-4627-b74a"}},
+		{"https://products-abc123.svc.us-east-1-aws.pinecone.io:443/query", indexHost{"products", "abc123", "us-east-1-aws"}},
+	}
+	for _, c := range cases {
+		if got, err := parseIndexHost(c.host); err != nil || got != c.want {
+			t.Errorf("%s: expected %+v, got %+v: %v", c.host, c.want, got, err)
+		}
+	}
+
+	for _, host := range []string{"", "products", "products-abc123.svc.pinecone.io", "products.svc.us-east-1-aws.pinecone.io", "products-abc123.svc.us-east-1-aws.example.com", "-abc123.svc.us-east-1-aws.pinecone.io"} {
+		if _, err := parseIndexHost(host); err == nil {
+			t.Errorf("%q: expected an error", host)
+		}
+	}
+}
+
+func TestIndexHostPartsFunction(t *testing.T) {
+	result, err := runTestFunction(t, NewIndexHostPartsFunction(), types.StringValue("docs-example-4zo0ijk.svc.us-west1-gcp.pinecone.io"))
+	want := types.ObjectValueMust(indexHostPartsAttrTypes, map[string]attr.Value{
+		"name":       types.StringValue("docs-example"),
+		"project_id": types.StringValue("4zo0ijk"),
+		"region":     types.StringValue("us-west1-gcp"),
+	})
+	if err != nil || !result.Equal(want) {
+		t.Errorf("expected %v, got %v: %v", want, result, err)
+	}
+
+	if _, err := runTestFunction(t, NewIndexHostPartsFunction(), types.StringValue("localhost:5080")); err == nil {
+		t.Error("expected an error for a host that is not an index host")
+	}
+}
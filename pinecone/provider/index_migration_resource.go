@@ -0,0 +1,438 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+const (
+	defaultIndexMigrationCreateTimeout time.Duration = 60 * time.Minute
+	defaultIndexMigrationReadTimeout   time.Duration = 5 * time.Minute
+	// indexMigrationVerifyTimeout bounds how long the vector count of the target may lag
+	// behind that of the collection.
+	indexMigrationVerifyTimeout time.Duration = 10 * time.Minute
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &IndexMigrationResource{}
+var _ resource.ResourceWithModifyPlan = &IndexMigrationResource{}
+
+func NewIndexMigrationResource() resource.Resource {
+	return &IndexMigrationResource{PineconeResource: &PineconeResource{}}
+}
+
+// IndexMigrationResource migrates a pod index to a new serverless index, through a
+// collection.
+type IndexMigrationResource struct {
+	*PineconeResource
+}
+
+func (r *IndexMigrationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_index_migration"
+}
+
+func (r *IndexMigrationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Migrates a pod index to serverless. The source index is snapshotted into a collection, a serverless " +
+			"index is created from the collection, and the migration is verified once the new index is ready and holds as many vectors " +
+			"as the collection. When the counts still differ after 10 minutes, the migration completes with a warning and keeps the " +
+			"collection. A collection or target index left by an earlier attempt is reused when its settings match. " +
+			"The source index is left untouched. Destroying the migration only removes it from the state, both indexes are kept.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Migration identifier, the name of the target index.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"source_index": schema.StringAttribute{
+				MarkdownDescription: "The name of the pod index to migrate.",
+				Required:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"collection": schema.StringAttribute{
+				MarkdownDescription: "The name of the collection the source index is snapshotted into. Defaults to the name of the source index followed by `-migration`.",
+				Optional:            true,
+				Computed:            true,
+				Validators:          []validator.String{stringvalidator.LengthAtMost(45)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"keep_collection": schema.BoolAttribute{
+				MarkdownDescription: "Whether to keep the collection once the migration has been verified. The collection is always kept when the migration fails. Defaults to false.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"target_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the serverless index to create. The maximum length is 45 characters.",
+				Required:            true,
+				Validators:          []validator.String{stringvalidator.LengthAtMost(45)},
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"target_cloud": schema.StringAttribute{
+				MarkdownDescription: "The public cloud where the serverless index is hosted. One of 'gcp', 'aws' or 'azure'.",
+				Required:            true,
+				Validators:          []validator.String{stringvalidator.OneOf("gcp", "aws", "azure")},
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"target_region": schema.StringAttribute{
+				MarkdownDescription: "The region where the serverless index is hosted.",
+				Required:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+			},
+			"target_host": schema.StringAttribute{
+				MarkdownDescription: "The URL address where the serverless index is hosted.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"target_status": schema.SingleNestedAttribute{
+				MarkdownDescription: "The status of the serverless index.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Object{objectplanmodifier.UseStateForUnknown()},
+				Attributes: map[string]schema.Attribute{
+					"ready": schema.BoolAttribute{
+						MarkdownDescription: "Whether the index is ready to serve requests.",
+						Computed:            true,
+					},
+					"state": schema.StringAttribute{
+						MarkdownDescription: "The state of the index.",
+						Computed:            true,
+					},
+				},
+			},
+			"source_vector_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of vectors in the collection the serverless index is created from.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"target_vector_count": schema.Int64Attribute{
+				MarkdownDescription: "The number of vectors in the serverless index when the migration was verified. It differs from `source_vector_count` when the verification failed.",
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx,
+				timeouts.Opts{
+					Create:            true,
+					CreateDescription: timeoutDescription(defaultIndexMigrationCreateTimeout),
+					Read:              true,
+					ReadDescription:   timeoutDescription(defaultIndexMigrationReadTimeout),
+				},
+			),
+		},
+	}
+	addAttributes(resp.Schema.Attributes, credentialResourceAttributes("migration", r.PineconeResource))
+}
+
+func (r *IndexMigrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.denyReadOnlyPlan(req, resp, "index migration")
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() || r.policy == nil {
+		return
+	}
+
+	// The target is an index like any other, so the policy applies to it.
+	var data models.IndexMigrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	var prior *models.IndexMigrationResourceModel
+	if !req.State.Raw.IsNull() {
+		prior = &models.IndexMigrationResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, prior)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.policy.CheckMigration(&data, prior)...)
+}
+
+func (r *IndexMigrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("create", "index migration")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data models.IndexMigrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, diags := data.Timeouts.Create(ctx, timeoutOrDefault(r.timeouts.Create, defaultIndexMigrationCreateTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	source, err := api.DescribeIndex(ctx, data.SourceIndex.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source_index"), "Failed to describe source index", err.Error())
+		return
+	}
+	if source.Spec == nil || source.Spec.Pod == nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source_index"), "Source index is not a pod index",
+			fmt.Sprintf("Index %s is already serverless. Only pod indexes can be migrated.", source.Name))
+		return
+	}
+	if data.Collection.IsUnknown() {
+		data.Collection = types.StringValue(source.Name + "-migration")
+	}
+	collection := data.Collection.ValueString()
+
+	// Snapshot the source index. A collection left by an earlier attempt is reused.
+	_, err = api.CreateCollection(ctx, &pinecone.CreateCollectionRequest{Name: collection, Source: source.Name})
+	if err != nil {
+		if !isAlreadyExistsError(err) {
+			resp.Diagnostics.AddAttributeError(path.Root("collection"), "Failed to create collection", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(adoptMigrationCollection(ctx, api, collection, source)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("reusing collection %s", collection))
+	}
+	var snapshot *pinecone.Collection
+	w := r.newWaiter("collection", collection, createTimeout, func(ctx context.Context) (string, waiter.Class, error) {
+		c, err := api.DescribeCollection(ctx, collection)
+		if err != nil {
+			return "", waiter.Pending, err
+		}
+		snapshot = c
+		return string(c.Status), classifyCollectionCreateState(c.Status), nil
+	})
+	if _, err := w.Wait(ctx); err != nil {
+		resp.Diagnostics.AddError("Failed to wait for collection to become ready.", err.Error())
+		return
+	}
+
+	// The target is compared with the collection rather than the source index, which
+	// may have been written to since it was snapshotted.
+	var sourceCount uint32
+	if snapshot.VectorCount != nil {
+		sourceCount = uint32(*snapshot.VectorCount)
+	} else {
+		count, err := indexVectorCount(ctx, api, source.Host)
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to count the vectors of the source index", err.Error())
+			return
+		}
+		sourceCount = count
+	}
+	data.SourceVectorCount = types.Int64Value(int64(sourceCount))
+
+	// Create the serverless index from the collection. A target left by an earlier
+	// attempt is adopted. From here on the target index exists, so every observation is
+	// saved into state.
+	target := &pinecone.CreateServerlessIndexRequest{
+		Name:      data.TargetName.ValueString(),
+		Dimension: source.Dimension,
+		Metric:    source.Metric,
+		Cloud:     pinecone.Cloud(data.TargetCloud.ValueString()),
+		Region:    data.TargetRegion.ValueString(),
+	}
+	_, err = api.CreateServerlessIndexFromCollection(ctx, target, collection)
+	if err != nil {
+		if !isAlreadyExistsError(err) {
+			resp.Diagnostics.AddAttributeError(path.Root("target_name"), "Failed to create target index", err.Error())
+			return
+		}
+		resp.Diagnostics.Append(adoptMigrationTarget(ctx, api, target)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		tflog.Info(ctx, fmt.Sprintf("adopting existing target index %s", target.Name))
+	}
+	data.TargetVectorCount = types.Int64Null()
+
+	w = r.newWaiter("index", data.TargetName.ValueString(), createTimeout, func(ctx context.Context) (string, waiter.Class, error) {
+		index, err := api.DescribeIndex(ctx, data.TargetName.ValueString())
+		if err != nil {
+			return "", waiter.Pending, err
+		}
+		resp.Diagnostics.Append(data.ReadTarget(ctx, index)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return string(index.Status.State), classifyIndexCreateState(index.Status), nil
+	})
+	if _, err := w.Wait(ctx); err != nil {
+		resp.Diagnostics.AddError("Failed to wait for target index to become ready.", err.Error())
+		return
+	}
+
+	// Verify the migration. Serverless indexes report new vectors with a delay, so the
+	// count is polled until it matches. A mismatch leaves the target in place: it is
+	// reported in target_vector_count and a warning, and the collection is kept.
+	w = r.newWaiter("index", data.TargetName.ValueString(), min(createTimeout, indexMigrationVerifyTimeout), func(ctx context.Context) (string, waiter.Class, error) {
+		count, err := indexVectorCount(ctx, api, data.TargetHost.ValueString())
+		if err != nil {
+			return "", waiter.Pending, err
+		}
+		data.TargetVectorCount = types.Int64Value(int64(count))
+		class := waiter.Pending
+		if count == sourceCount {
+			class = waiter.Target
+		}
+		return fmt.Sprintf("%d of %d vectors", count, sourceCount), class, nil
+	})
+	if _, err := w.Wait(ctx); err != nil {
+		var timeout *waiter.TimeoutError
+		if !errors.As(err, &timeout) {
+			resp.Diagnostics.AddError("Failed to verify migration", err.Error())
+			return
+		}
+		resp.Diagnostics.AddAttributeWarning(path.Root("target_vector_count"), "Migration not verified",
+			fmt.Sprintf("Index %s should hold the %d vectors of collection %s, but holds %s: %s. "+
+				"Collection %s has been kept. Compare the indexes before relying on %s.",
+				data.TargetName.ValueString(), sourceCount, collection, data.TargetVectorCount, err, collection, data.TargetName.ValueString()))
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if !data.KeepCollection.ValueBool() {
+		if err := r.deleteCollection(ctx, api, collection, createTimeout); err != nil {
+			resp.Diagnostics.AddWarning("Failed to delete migration collection",
+				fmt.Sprintf("The migration succeeded, but collection %s could not be deleted: %s", collection, err))
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// adoptMigrationCollection returns an error unless the existing collection name can hold a
+// snapshot of source.
+func adoptMigrationCollection(ctx context.Context, api client.ControlPlane, name string, source *pinecone.Index) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	collection, err := api.DescribeCollection(ctx, name)
+	if err != nil {
+		diags.AddAttributeError(path.Root("collection"), "Failed to describe collection", err.Error())
+		return diags
+	}
+	if (collection.Dimension != nil && *collection.Dimension != source.Dimension) || collection.Environment != source.Spec.Pod.Environment {
+		diags.AddAttributeError(path.Root("collection"), "Collection already exists",
+			fmt.Sprintf("Collection %s already exists, but was not taken from an index like %s. "+
+				"Delete it, or choose another name with the collection attribute.", name, source.Name))
+	}
+	return diags
+}
+
+// adoptMigrationTarget returns an error unless the existing index named by target has the
+// settings of target, as it would after an earlier attempt of the migration.
+func adoptMigrationTarget(ctx context.Context, api client.ControlPlane, target *pinecone.CreateServerlessIndexRequest) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	index, err := api.DescribeIndex(ctx, target.Name)
+	if err != nil {
+		diags.AddAttributeError(path.Root("target_name"), "Failed to describe target index", err.Error())
+		return diags
+	}
+	serverless := index.Spec != nil && index.Spec.Serverless != nil
+	if !serverless || index.Spec.Serverless.Cloud != target.Cloud || index.Spec.Serverless.Region != target.Region ||
+		index.Dimension != target.Dimension || index.Metric != target.Metric {
+		diags.AddAttributeError(path.Root("target_name"), "Target index already exists",
+			fmt.Sprintf("Index %s already exists with other settings than the migration would create it with. "+
+				"Delete it, or choose another target_name.", target.Name))
+	}
+	return diags
+}
+
+func (r *IndexMigrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.IndexMigrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, timeoutOrDefault(r.timeouts.Read, defaultIndexMigrationReadTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	index, err := api.DescribeIndex(ctx, data.TargetName.ValueString())
+	if err != nil {
+		if isNotFoundError(err) {
+			// The target index is gone, so the migration has to run again.
+			resp.State.RemoveResource(ctx)
+		} else {
+			resp.Diagnostics.AddError("Failed to describe target index", err.Error())
+		}
+		return
+	}
+
+	resp.Diagnostics.Append(data.ReadTarget(ctx, index)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *IndexMigrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("update", "index migration")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every attribute of the migration itself requires replacement, so only settings
+	// such as keep_collection and timeouts, and credentials of the same project, change
+	// here.
+	var data models.IndexMigrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *IndexMigrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("delete", "index migration")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The indexes outlive the migration, and the framework removes it from state.
+	var data models.IndexMigrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	tflog.Info(ctx, fmt.Sprintf("forgetting migration of index %s, both indexes are kept", data.SourceIndex.ValueString()))
+}
@@ -0,0 +1,241 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+// newTestMigrationMemory returns a Memory holding a pod index named "legacy" with two
+// vectors.
+func newTestMigrationMemory() *client.Memory {
+	memory := client.NewMemory()
+	memory.PutIndex(&pinecone.Index{
+		Name:      "legacy",
+		Dimension: 8,
+		Metric:    pinecone.Dotproduct,
+		Host:      "legacy-memory.svc.pinecone.io",
+		Spec:      &pinecone.IndexSpec{Pod: &pinecone.PodSpec{Environment: "us-west4-gcp", PodType: "s1.x1"}},
+		Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	})
+	memory.PutVectors("legacy-memory.svc.pinecone.io", "", &pinecone.Vector{Id: "a"}, &pinecone.Vector{Id: "b"})
+	return memory
+}
+
+func testIndexMigrationPlan(source string) models.IndexMigrationResourceModel {
+	return models.IndexMigrationResourceModel{
+		Id:                types.StringUnknown(),
+		SourceIndex:       types.StringValue(source),
+		Collection:        types.StringUnknown(),
+		KeepCollection:    types.BoolValue(false),
+		TargetName:        types.StringValue("modern"),
+		TargetCloud:       types.StringValue("aws"),
+		TargetRegion:      types.StringValue("us-east-1"),
+		TargetHost:        types.StringUnknown(),
+		TargetStatus:      types.ObjectUnknown(models.IndexStatusModel{}.AttrTypes()),
+		SourceVectorCount: types.Int64Unknown(),
+		TargetVectorCount: types.Int64Unknown(),
+		Timeouts: timeouts.Value{Object: types.ObjectNull(map[string]attr.Type{
+			"create": types.StringType,
+			"read":   types.StringType,
+		})},
+	}
+}
+
+func TestIndexMigrationResource_lifecycle(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestMigrationMemory()
+	memory.ReadyAfter = 2
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, newTestProviderData(memory))
+
+	created := createTestResource(t, r, testIndexMigrationPlan("legacy"))
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+	var state models.IndexMigrationResourceModel
+	created.State.Get(ctx, &state)
+	if state.Id.ValueString() != "modern" || state.TargetHost.ValueString() != "modern-memory.svc.us-east-1-aws.pinecone.io" {
+		t.Errorf("unexpected target in state: %v %v", state.Id, state.TargetHost)
+	}
+	if state.SourceVectorCount.ValueInt64() != 2 || state.TargetVectorCount.ValueInt64() != 2 {
+		t.Errorf("unexpected vector counts %v and %v", state.SourceVectorCount, state.TargetVectorCount)
+	}
+	if state.Collection.ValueString() != "legacy-migration" {
+		t.Errorf("unexpected collection %v", state.Collection)
+	}
+	if _, err := memory.DescribeCollection(ctx, "legacy-migration"); !isNotFoundError(err) {
+		t.Errorf("expected the collection to be deleted, got: %v", err)
+	}
+	target, err := memory.DescribeIndex(ctx, "modern")
+	if err != nil || target.Metric != pinecone.Dotproduct || target.Dimension != 8 {
+		t.Errorf("unexpected target index %+v: %v", target, err)
+	}
+
+	// Destroying the migration keeps both indexes.
+	deleteResp := &fwresource.DeleteResponse{State: created.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: created.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("delete failed: %v", deleteResp.Diagnostics)
+	}
+	if memory.Calls(client.OpDeleteIndex) != 0 {
+		t.Error("expected no index to be deleted")
+	}
+
+	// A target deleted out of band removes the migration from state.
+	memory.DeleteIndex(ctx, "modern")
+	readResp := &fwresource.ReadResponse{State: created.State}
+	r.Read(ctx, fwresource.ReadRequest{State: created.State}, readResp)
+	if readResp.Diagnostics.HasError() || !readResp.State.Raw.IsNull() {
+		t.Errorf("expected the migration to be removed from state, got: %v", readResp.Diagnostics)
+	}
+}
+
+func TestIndexMigrationResource_keepCollection(t *testing.T) {
+	memory := newTestMigrationMemory()
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, newTestProviderData(memory))
+
+	plan := testIndexMigrationPlan("legacy")
+	plan.Collection = types.StringValue("legacy-snapshot")
+	plan.KeepCollection = types.BoolValue(true)
+	created := createTestResource(t, r, plan)
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+	if _, err := memory.DescribeCollection(context.Background(), "legacy-snapshot"); err != nil {
+		t.Errorf("expected the collection to be kept, got: %v", err)
+	}
+}
+
+func TestIndexMigrationResource_serverlessSource(t *testing.T) {
+	memory := newTestMigrationMemory()
+	memory.PutIndex(&pinecone.Index{
+		Name: "modern",
+		Spec: &pinecone.IndexSpec{Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-east-1"}},
+	})
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, newTestProviderData(memory))
+
+	created := createTestResource(t, r, testIndexMigrationPlan("modern"))
+	if !created.Diagnostics.HasError() || created.Diagnostics.Errors()[0].Summary() != "Source index is not a pod index" {
+		t.Fatalf("expected a serverless source to be rejected, got: %v", created.Diagnostics)
+	}
+	if memory.Calls(client.OpCreateCollection) != 0 {
+		t.Error("expected no collection to be created")
+	}
+}
+
+func TestIndexMigrationResource_writtenDuringSnapshot(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestMigrationMemory()
+	// A vector written while the source is snapshotted ends up in the collection, which
+	// the target is verified against.
+	memory.Hook = func(op client.Operation, name string) error {
+		if op == client.OpCreateCollection {
+			memory.PutVectors("legacy-memory.svc.pinecone.io", "", &pinecone.Vector{Id: "c"})
+		}
+		return nil
+	}
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, newTestProviderData(memory))
+
+	created := createTestResource(t, r, testIndexMigrationPlan("legacy"))
+	if len(created.Diagnostics) > 0 {
+		t.Fatalf("expected the migration to be verified, got: %v", created.Diagnostics)
+	}
+	var state models.IndexMigrationResourceModel
+	created.State.Get(ctx, &state)
+	if state.SourceVectorCount.ValueInt64() != 3 || state.TargetVectorCount.ValueInt64() != 3 {
+		t.Errorf("unexpected vector counts %v and %v", state.SourceVectorCount, state.TargetVectorCount)
+	}
+}
+
+func TestIndexMigrationResource_countMismatch(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestMigrationMemory()
+	// A vector written to the target before it is verified makes the counts differ.
+	memory.Hook = func(op client.Operation, name string) error {
+		if op == client.OpDescribeIndexStats && name == "modern-memory.svc.us-east-1-aws.pinecone.io" {
+			memory.PutVectors(name, "", &pinecone.Vector{Id: "c"})
+		}
+		return nil
+	}
+	data := newTestProviderData(memory)
+	data.Timeouts = DefaultTimeouts{Create: 50 * time.Millisecond}
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, data)
+
+	created := createTestResource(t, r, testIndexMigrationPlan("legacy"))
+	if created.Diagnostics.HasError() || created.Diagnostics.WarningsCount() != 1 ||
+		!strings.Contains(created.Diagnostics.Warnings()[0].Detail(), "Collection legacy-migration has been kept") {
+		t.Fatalf("expected the verification to warn, got: %v", created.Diagnostics)
+	}
+	var state models.IndexMigrationResourceModel
+	created.State.Get(ctx, &state)
+	if state.TargetHost.IsNull() || state.SourceVectorCount.ValueInt64() != 2 || state.TargetVectorCount.ValueInt64() != 3 {
+		t.Errorf("expected the target to be saved with its vector count, got: %v %v %v", state.TargetHost, state.SourceVectorCount, state.TargetVectorCount)
+	}
+	if _, err := memory.DescribeCollection(ctx, "legacy-migration"); err != nil {
+		t.Errorf("expected the collection to be kept, got: %v", err)
+	}
+}
+
+func TestIndexMigrationResource_adoptExisting(t *testing.T) {
+	ctx := context.Background()
+	memory := newTestMigrationMemory()
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, newTestProviderData(memory))
+
+	plan := testIndexMigrationPlan("legacy")
+	plan.KeepCollection = types.BoolValue(true)
+	if created := createTestResource(t, r, plan); created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+
+	// Running the migration again, as after it was tainted, reuses both the collection
+	// and the target index.
+	created := createTestResource(t, r, plan)
+	if created.Diagnostics.HasError() {
+		t.Fatalf("expected the collection and target to be adopted, got: %v", created.Diagnostics)
+	}
+	if got := memory.Calls(client.OpCreateServerlessIndexFromCollection); got != 2 {
+		t.Errorf("expected 2 attempts to create the target, got %d", got)
+	}
+
+	// A target with other settings is not.
+	memory.DeleteIndex(ctx, "modern")
+	memory.PutIndex(&pinecone.Index{
+		Name:      "modern",
+		Dimension: 16,
+		Metric:    pinecone.Dotproduct,
+		Spec:      &pinecone.IndexSpec{Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-east-1"}},
+		Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	})
+	created = createTestResource(t, r, plan)
+	if !created.Diagnostics.HasError() || created.Diagnostics.Errors()[0].Summary() != "Target index already exists" {
+		t.Errorf("expected a conflicting target to be rejected, got: %v", created.Diagnostics)
+	}
+}
+
+func TestIndexMigrationResource_policy(t *testing.T) {
+	ctx := context.Background()
+	data := newTestProviderData(newTestMigrationMemory())
+	data.Policy = &IndexPolicy{AllowedRegions: []string{"us-west-2"}}
+	r := newTestResource[*IndexMigrationResource](t, NewIndexMigrationResource, data)
+	s := testResourceSchema(t, r)
+
+	model := testIndexMigrationPlan("legacy")
+	plan := newTestPlan(t, s, &model)
+	resp := &fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: newTestState(s)}, resp)
+	if got := attributePaths(resp.Diagnostics); len(got) != 1 || got[0] != "target_region" {
+		t.Errorf("expected the target region to be refused, got: %v", resp.Diagnostics)
+	}
+}
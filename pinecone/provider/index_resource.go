@@ -5,31 +5,32 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
-	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
-	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
-	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
 	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
 )
 
+// privateKeyPendingCreate marks an index whose create timed out while it was still initializing.
+const privateKeyPendingCreate = "pending_create"
+
 const (
 	defaultIndexCreateTimeout time.Duration = 10 * time.Minute
+	defaultIndexReadTimeout   time.Duration = 5 * time.Minute
 	defaultIndexUpdateTimeout time.Duration = 10 * time.Minute
 	defaultIndexDeleteTimeout time.Duration = 10 * time.Minute
 )
@@ -37,6 +38,8 @@ const (
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &IndexResource{}
 var _ resource.ResourceWithImportState = &IndexResource{}
+var _ resource.ResourceWithIdentity = &IndexResource{}
+var _ resource.ResourceWithModifyPlan = &IndexResource{}
 
 func NewIndexResource() resource.Resource {
 	return &IndexResource{PineconeResource: &PineconeResource{}}
@@ -49,6 +52,12 @@ type IndexResource struct {
 
 func (r *IndexResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_index"
+	// The identity is updated when the project resolved for it changes.
+	resp.ResourceBehavior.MutableIdentity = true
+}
+
+func (r *IndexResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("index")
 }
 
 func (r *IndexResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -56,167 +65,69 @@ func (r *IndexResource) Schema(ctx context.Context, req resource.SchemaRequest,
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Index resource",
 
-		Attributes: map[string]schema.Attribute{
-			"id": schema.StringAttribute{
-				MarkdownDescription: "Index identifier",
-				Computed:            true,
-			},
-			"name": schema.StringAttribute{
-				MarkdownDescription: "The name of the index to be created. The maximum length is 45 characters.",
-				Required:            true,
-				Validators: []validator.String{
-					stringvalidator.LengthAtMost(45),
-				},
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
-			},
-			"dimension": schema.Int64Attribute{
-				MarkdownDescription: "The dimensions of the vectors to be inserted in the index",
-				Required:            true,
-				Validators: []validator.Int64{
-					int64validator.AtLeast(1),
-				},
-				PlanModifiers: []planmodifier.Int64{
-					int64planmodifier.RequiresReplace(),
-				},
-			},
-			"metric": schema.StringAttribute{
-				MarkdownDescription: "The distance metric to be used for similarity search. You can use 'euclidean', 'cosine', or 'dotproduct'.",
-				Optional:            true,
-				Computed:            true,
-				Default:             stringdefault.StaticString("cosine"),
-				Validators: []validator.String{
-					stringvalidator.OneOf([]string{"euclidean", "cosine", "dotproduct"}...),
-				},
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
-			},
-			"host": schema.StringAttribute{
-				MarkdownDescription: "The URL address where the index is hosted.",
-				Computed:            true,
-			},
-			"spec": schema.SingleNestedAttribute{
-				Description: "Spec",
-				Required:    true,
-				Attributes: map[string]schema.Attribute{
-					"pod": schema.SingleNestedAttribute{
-						Description: "Configuration needed to deploy a pod-based index.",
-						Optional:    true,
-						Attributes: map[string]schema.Attribute{
-							"environment": schema.StringAttribute{
-								MarkdownDescription: "The environment where the index is hosted.",
-								Required:            true,
-								PlanModifiers: []planmodifier.String{
-									stringplanmodifier.RequiresReplace(),
-								},
-							},
-							"replicas": schema.Int64Attribute{
-								MarkdownDescription: "The number of replicas. Replicas duplicate your index. They provide higher availability and throughput. Replicas can be scaled up or down as your needs change.",
-								Optional:            true,
-								Computed:            true,
-								Default:             int64default.StaticInt64(1),
-								PlanModifiers: []planmodifier.Int64{
-									int64planmodifier.RequiresReplace(),
-								},
-							},
-							"shards": schema.Int64Attribute{
-								MarkdownDescription: "The number of shards. Shards split your data across multiple pods so you can fit more data into an index.",
-								Optional:            true,
-								Computed:            true,
-								Default:             int64default.StaticInt64(1),
-								PlanModifiers: []planmodifier.Int64{
-									int64planmodifier.RequiresReplace(),
-								},
-							},
-							"pod_type": schema.StringAttribute{
-								MarkdownDescription: "The type of pod to use. One of s1, p1, or p2 appended with . and one of x1, x2, x4, or x8.",
-								Required:            true,
-								PlanModifiers: []planmodifier.String{
-									stringplanmodifier.RequiresReplace(),
-								},
-							},
-							"pods": schema.Int64Attribute{
-								MarkdownDescription: "The number of pods to be used in the index. This should be equal to shards x replicas.'",
-								Computed:            true,
-							},
-							"metadata_config": schema.SingleNestedAttribute{
-								Description: "Configuration for the behavior of Pinecone's internal metadata index. By default, all metadata is indexed; when metadata_config is present, only specified metadata fields are indexed. These configurations are only valid for use with pod-based indexes.",
-								Optional:    true,
-								Computed:    true,
-								Attributes: map[string]schema.Attribute{
-									"indexed": schema.ListAttribute{
-										Description: "The indexed fields.",
-										Required:    true,
-										ElementType: types.StringType,
-									},
-								},
-							},
-							"source_collection": schema.StringAttribute{
-								MarkdownDescription: "The name of the collection to create an index from.",
-								Optional:            true,
-								PlanModifiers: []planmodifier.String{
-									stringplanmodifier.RequiresReplace(),
-								},
-							},
-						},
-					},
-					"serverless": schema.SingleNestedAttribute{
-						Description: "Configuration needed to deploy a serverless index.",
-						Optional:    true,
-						Attributes: map[string]schema.Attribute{
-							"cloud": schema.StringAttribute{
-								Description: "The public cloud where you would like your index hosted. [gcp|aws|azure]",
-								Required:    true,
-								PlanModifiers: []planmodifier.String{
-									stringplanmodifier.RequiresReplace(),
-								},
-							},
-							"region": schema.StringAttribute{
-								MarkdownDescription: "The region where you would like your index to be created.",
-								Required:            true,
-								PlanModifiers: []planmodifier.String{
-									stringplanmodifier.RequiresReplace(),
-								},
-							},
-						},
-					},
-				},
-			},
-			"status": schema.SingleNestedAttribute{
-				Description: "Status",
-				Computed:    true,
-				Attributes: map[string]schema.Attribute{
-					"ready": schema.BoolAttribute{
-						Description: "Ready.",
-						Computed:    true,
-					},
-					"state": schema.StringAttribute{
-						MarkdownDescription: "Initializing InitializationFailed ScalingUp ScalingDown ScalingUpPodSize ScalingDownPodSize Upgrading Terminating Ready",
-						Computed:            true,
-					},
-				},
-			},
-		},
+		Attributes: indexResourceAttributes(),
 		Blocks: map[string]schema.Block{
 			"timeouts": timeouts.Block(ctx,
 				timeouts.Opts{
-					Create: true,
-					CreateDescription: `Timeout defaults to 5 mins. Accepts a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
-						`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
-						`"s" (seconds), "m" (minutes), "h" (hours).`,
-					Delete: true,
-					DeleteDescription: `Timeout defaults to 5 mins. Accepts a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration) ` +
-						`consisting of numbers and unit suffixes, such as "30s" or "2h45m". Valid time units are ` +
-						`"s" (seconds), "m" (minutes), "h" (hours).`,
+					Create:            true,
+					CreateDescription: timeoutDescription(defaultIndexCreateTimeout),
+					Read:              true,
+					ReadDescription:   timeoutDescription(defaultIndexReadTimeout),
+					Update:            true,
+					UpdateDescription: timeoutDescription(defaultIndexUpdateTimeout),
+					Delete:            true,
+					DeleteDescription: timeoutDescription(defaultIndexDeleteTimeout),
 				},
 			),
 		},
 	}
+	addAttributes(resp.Schema.Attributes, credentialResourceAttributes("index", r.PineconeResource))
+}
+
+func (r *IndexResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	r.denyReadOnlyPlan(req, resp, "index")
+	if resp.Diagnostics.HasError() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data models.IndexResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if r.policy != nil {
+		var prior *models.IndexResourceModel
+		if !req.State.Raw.IsNull() {
+			prior = &models.IndexResourceModel{}
+			resp.Diagnostics.Append(req.State.Get(ctx, prior)...)
+		}
+		resp.Diagnostics.Append(r.policy.CheckChange(ctx, &data, prior)...)
+	}
+	resp.Diagnostics.Append(denyServerlessFinalSnapshot(ctx, &data)...)
+	r.warnReplacement(ctx, req, resp, &data)
+
+	resp.Diagnostics.Append(r.costs.Estimate(ctx, &data)...)
+	if !req.State.Raw.IsNull() {
+		var prior types.Float64
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("estimated_monthly_cost"), &prior)...)
+		resp.Diagnostics.Append(r.costs.WarnIncrease(data.Name.ValueString(), prior, data.EstimatedMonthlyCost)...)
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_hourly_cost"), data.EstimatedHourlyCost)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("estimated_monthly_cost"), data.EstimatedMonthlyCost)...)
 }
 
 func (r *IndexResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	r.create(ctx, req, resp, resp.Private)
+}
+
+// create creates the index. When the wait for it times out, the index is kept in state
+// and marked as pending in private.
+func (r *IndexResource) create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse, private privateState) {
+	resp.Diagnostics.Append(r.denyReadOnly("create", "index")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data models.IndexResourceModel
 
 	// Read Terraform plan data into the model
@@ -226,6 +137,12 @@ func (r *IndexResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var spec models.IndexSpecModel
 	resp.Diagnostics.Append(data.Spec.As(ctx, &spec, basetypes.ObjectAsOptions{})...)
 	if resp.Diagnostics.HasError() {
@@ -257,10 +174,12 @@ func (r *IndexResource) Create(ctx context.Context, req resource.CreateRequest,
 		}
 		podReq.MetadataConfig = metadataConfig
 
-		_, err := r.client.CreatePodIndex(ctx, &podReq)
+		_, err := api.CreatePodIndex(ctx, &podReq)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to create pod index", err.Error())
-			return
+			if err = r.recoverCreateConflict(ctx, api, err, &data, &spec); err != nil {
+				resp.Diagnostics.AddError("Failed to create pod index", err.Error())
+				return
+			}
 		}
 	}
 
@@ -273,50 +192,55 @@ func (r *IndexResource) Create(ctx context.Context, req resource.CreateRequest,
 			Region:    spec.Serverless.Region.ValueString(),
 		}
 
-		_, err := r.client.CreateServerlessIndex(ctx, &serverlessReq)
+		_, err := api.CreateServerlessIndex(ctx, &serverlessReq)
 		if err != nil {
-			resp.Diagnostics.AddError("Failed to create serverless index", err.Error())
-			return
+			if err = r.recoverCreateConflict(ctx, api, err, &data, &spec); err != nil {
+				resp.Diagnostics.AddError("Failed to create serverless index", err.Error())
+				return
+			}
 		}
 	}
 
 	// Wait for index to be ready
 	// Create() is passed a default timeout to use if no value
 	// has been supplied in the Terraform configuration.
-	createTimeout, diags := data.Timeouts.Create(ctx, defaultIndexCreateTimeout)
+	createTimeout, diags := data.Timeouts.Create(ctx, timeoutOrDefault(r.timeouts.Create, defaultIndexCreateTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err := retry.RetryContext(ctx, createTimeout, func() *retry.RetryError {
-		index, err := r.client.DescribeIndex(ctx, data.Name.ValueString())
-
-		resp.Diagnostics.Append(data.Read(ctx, index)...)
-
-		// Save current status to state
-		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-
-		if err != nil {
-			return retry.NonRetryableError(err)
-		}
-		if !index.Status.Ready {
-			return retry.RetryableError(fmt.Errorf("index not ready. State: %s", index.Status.State))
-		}
-		return nil
-	})
+	diags, err := r.waitForIndexReady(ctx, api, &data, createTimeout, &resp.State)
+	resp.Diagnostics.Append(diags...)
 	if err != nil {
+		var timeoutErr *waiter.TimeoutError
+		if errors.As(err, &timeoutErr) && !data.Id.IsUnknown() {
+			// The index exists but is still initializing. Keep it in state and let the
+			// next Read resume the wait rather than orphaning it.
+			resp.Diagnostics.Append(private.SetKey(ctx, privateKeyPendingCreate, []byte("true"))...)
+			resp.Diagnostics.AddWarning("Index creation still in progress",
+				fmt.Sprintf("%s. The index has been saved to state and the next refresh resumes waiting for it to become ready.", err))
+			return
+		}
 		resp.Diagnostics.AddError("Failed to wait for index to become ready.", err.Error())
 		return
 	}
-
-	// resp.Diagnostics.Append(data.Read(ctx, index)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setIndexIdentity(ctx, resp.Identity, r.clients, api, &data)...)
 }
 
 func (r *IndexResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The private state of the response starts out as that of the request.
+	r.read(ctx, req, resp, resp.Private)
+}
+
+// read refreshes the index, resuming the wait for an index marked as pending in private.
+func (r *IndexResource) read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse, private privateState) {
 	var data models.IndexResourceModel
 
 	// Read Terraform prior state data into the model
@@ -326,9 +250,30 @@ func (r *IndexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	index, err := r.client.DescribeIndex(ctx, data.Id.ValueString())
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	readTimeout, diags := data.Timeouts.Read(ctx, timeoutOrDefault(r.timeouts.Read, defaultIndexReadTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	pending, diags := private.GetKey(ctx, privateKeyPendingCreate)
+	resp.Diagnostics.Append(diags...)
+	if len(pending) > 0 {
+		r.resumePendingRead(ctx, api, &data, readTimeout, resp, private)
+		return
+	}
+
+	index, err := api.DescribeIndex(ctx, data.Id.ValueString())
 	if err != nil {
-		if err.Error() == fmt.Sprintf("failed to describe idx: Resource %s not found", data.Id.ValueString()) {
+		if isNotFoundError(err) {
 			resp.State.RemoveResource(ctx)
 		} else {
 			resp.Diagnostics.AddError("Failed to describe index", err.Error())
@@ -337,16 +282,72 @@ func (r *IndexResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	}
 
 	data.Read(ctx, index)
+	resp.Diagnostics.Append(r.costs.Estimate(ctx, &data)...)
+	if data.WaitForReady.IsNull() {
+		data.WaitForReady = types.BoolValue(true)
+	}
+	if data.AdoptExisting.IsNull() {
+		data.AdoptExisting = types.BoolValue(false)
+	}
+	if data.ForceDestroy.IsNull() {
+		data.ForceDestroy = types.BoolValue(false)
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(setIndexIdentity(ctx, resp.Identity, r.clients, api, &data)...)
 }
 
 func (r *IndexResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Update not supported.
+	resp.Diagnostics.Append(r.denyReadOnly("update", "index")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data models.IndexResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, timeoutOrDefault(r.timeouts.Update, defaultIndexUpdateTimeout))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every attribute of the index itself requires replacement, so only provider
+	// settings such as wait_for_ready and timeouts change here. Refresh the index
+	// so the computed attributes are known again.
+	diags, err := r.waitForIndexReady(ctx, api, &data, updateTimeout, &resp.State)
+	resp.Diagnostics.Append(diags...)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to wait for index to become ready.", err.Error())
+		return
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *IndexResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.Diagnostics.Append(r.denyReadOnly("delete", "index")...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var data models.IndexResourceModel
 
 	// Read Terraform prior state data into the model
@@ -356,37 +357,306 @@ func (r *IndexResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	err := r.client.DeleteIndex(ctx, data.Name.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to delete index", err.Error())
+	api, diags := r.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Wait for index to be deleted
-	// Create() is passed a default timeout to use if no value
+	// Delete() is passed a default timeout to use if no value
 	// has been supplied in the Terraform configuration.
-	deleteTimeout, diags := data.Timeouts.Create(ctx, defaultIndexDeleteTimeout)
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, timeoutOrDefault(r.timeouts.Delete, defaultIndexDeleteTimeout))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err = retry.RetryContext(ctx, deleteTimeout, func() *retry.RetryError {
-		index, err := r.client.DescribeIndex(ctx, data.Id.ValueString())
+	snapshot, diags := r.createFinalSnapshot(ctx, api, &data, deleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if snapshot != "" {
+		resp.Diagnostics.AddWarning("Final snapshot created",
+			fmt.Sprintf("Collection %s holds a copy of index %s taken before it was deleted. "+
+				"Create an index from it with source_collection to restore the vectors.", snapshot, data.Name.ValueString()))
+	} else if !data.ForceDestroy.ValueBool() {
+		// The vectors of an index with a final snapshot are kept in the snapshot.
+		resp.Diagnostics.Append(denyNonEmptyDelete(ctx, api, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// A delete that was retried after a server error can find the index already gone.
+	err := api.DeleteIndex(ctx, data.Name.ValueString())
+	if err != nil && !isNotFoundError(err) {
+		resp.Diagnostics.AddError("Failed to delete index", err.Error())
+		return
+	}
+
+	// Wait for index to be deleted
+
+	w := r.newWaiter("index", data.Name.ValueString(), deleteTimeout, func(ctx context.Context) (string, waiter.Class, error) {
+		index, err := api.DescribeIndex(ctx, data.Id.ValueString())
 		if err != nil {
-			if strings.Contains(err.Error(), "not found") {
-				return nil
+			if isNotFoundError(err) {
+				return deletedState, waiter.Target, nil
 			}
-			return retry.NonRetryableError(err)
+			return "", waiter.Pending, err
 		}
-		return retry.RetryableError(fmt.Errorf("index not deleted. State: %s", index.Status.State))
+		return string(index.Status.State), waiter.Pending, nil
 	})
-	if err != nil {
+	if _, err := w.Wait(ctx); err != nil {
 		resp.Diagnostics.AddError("Failed to wait for index to be deleted.", err.Error())
 		return
 	}
 }
 
+// ImportState accepts the name of the index, a project_id/name pair, the host of the
+// index or its identity, and checks that the index exists in the project of the
+// provider.
 func (r *IndexResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	var id importID
+	if req.ID == "" && req.Identity != nil {
+		var diags diag.Diagnostics
+		id, diags = importIDFromIdentity(ctx, req.Identity)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		var err error
+		if id, err = parseImportID(req.ID, true); err != nil {
+			resp.Diagnostics.AddError("Invalid import ID",
+				fmt.Sprintf("Import an index by its name, a project_id/name pair or its host: %s.", err))
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	api, diags := r.clientFor(ctx, types.StringNull(), types.StringNull())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeoutOrDefault(r.timeouts.Read, defaultIndexReadTimeout))
+	defer cancel()
+
+	keyProjectID, diags := checkImportProject(ctx, r.clients, api, "index", id)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	index, err := api.DescribeIndex(ctx, id.Name)
+	if err != nil {
+		if isNotFoundError(err) {
+			resp.Diagnostics.AddError("Index not found",
+				fmt.Sprintf("Index %s does not exist in the project of the provider's API key, so it cannot be imported.", id.Name))
+		} else {
+			resp.Diagnostics.AddError("Failed to describe index", err.Error())
+		}
+		return
+	}
+	projectID, _ := projectIDFromHost(index.Name, index.Host)
+	resp.Diagnostics.Append(verifyImportProject("index", id, projectID, keyProjectID)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), index.Name)...)
+	if projectID == "" {
+		projectID = keyProjectID
+	}
+	resp.Diagnostics.Append(setResourceIdentity(ctx, resp.Identity, r.clients, api, index.Name, projectID)...)
+}
+
+// setIndexIdentity records the identity of the index described by data. Its project
+// is taken from its host.
+func setIndexIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, clients *ClientCache, api client.ControlPlane, data *models.IndexResourceModel) diag.Diagnostics {
+	projectID, _ := projectIDFromHost(data.Name.ValueString(), data.Host.ValueString())
+	return setResourceIdentity(ctx, identity, clients, api, data.Name.ValueString(), projectID)
+}
+
+// waitForIndexReady polls the index until it is ready, or only once when wait_for_ready
+// is disabled, saving every observation into state. The returned diagnostics cover
+// reading the index into state, the error covers the wait itself.
+func (r *IndexResource) waitForIndexReady(ctx context.Context, api client.ControlPlane, data *models.IndexResourceModel, timeout time.Duration, state *tfsdk.State) (diag.Diagnostics, error) {
+	var diags diag.Diagnostics
+
+	waitForReady := data.WaitForReady.ValueBool()
+	w := r.newWaiter("index", data.Name.ValueString(), timeout, func(ctx context.Context) (string, waiter.Class, error) {
+		index, err := api.DescribeIndex(ctx, data.Name.ValueString())
+		if err != nil {
+			return "", waiter.Pending, err
+		}
+
+		diags.Append(data.Read(ctx, index)...)
+		diags.Append(r.costs.Estimate(ctx, data)...)
+
+		// Save current status to state
+		diags.Append(state.Set(ctx, data)...)
+
+		class := classifyIndexCreateState(index.Status)
+		if !waitForReady && class == waiter.Pending {
+			class = waiter.Target
+		}
+		return string(index.Status.State), class, nil
+	})
+	_, err := w.Wait(ctx)
+	return diags, err
+}
+
+// recoverCreateConflict decides whether a failed create can continue with an existing index
+// of the same name. It returns nil to continue waiting on the existing index, or the error
+// to report. An existing index is used when:
+//   - a previous apply created it but timed out before recording it, so it is still
+//     initializing with the planned spec, or
+//   - adopt_existing is set and its dimension, metric and spec match the plan.
+func (r *IndexResource) recoverCreateConflict(ctx context.Context, api client.ControlPlane, createErr error, data *models.IndexResourceModel, spec *models.IndexSpecModel) error {
+	if !isAlreadyExistsError(createErr) {
+		return createErr
+	}
+
+	index, err := api.DescribeIndex(ctx, data.Name.ValueString())
+	if err != nil {
+		return createErr
+	}
+
+	adopt := data.AdoptExisting.ValueBool()
+	class := classifyIndexCreateState(index.Status)
+	if class == waiter.Failed || (class != waiter.Pending && !adopt) {
+		return createErr
+	}
+
+	if mismatches := indexSpecMismatches(index, data, spec); len(mismatches) > 0 {
+		if adopt {
+			return fmt.Errorf("%w; the existing index cannot be adopted because its %s", createErr, strings.Join(mismatches, ", "))
+		}
+		return createErr
+	}
+
+	if adopt {
+		tflog.Info(ctx, fmt.Sprintf("adopting existing index %s", index.Name), map[string]interface{}{
+			"state": string(index.Status.State),
+		})
+	} else {
+		tflog.Info(ctx, fmt.Sprintf("index %s is still initializing from a previous create, resuming wait", index.Name))
+	}
+	return nil
+}
+
+// resumePendingRead continues waiting on an index whose create timed out in an earlier apply.
+func (r *IndexResource) resumePendingRead(ctx context.Context, api client.ControlPlane, data *models.IndexResourceModel, timeout time.Duration, resp *resource.ReadResponse, private privateState) {
+	diags, err := r.waitForIndexReady(ctx, api, data, timeout, &resp.State)
+	resp.Diagnostics.Append(diags...)
+	if err != nil {
+		var timeoutErr *waiter.TimeoutError
+		switch {
+		case errors.As(err, &timeoutErr):
+			resp.Diagnostics.AddWarning("Index creation still in progress", err.Error())
+		case isNotFoundError(err):
+			resp.State.RemoveResource(ctx)
+		default:
+			resp.Diagnostics.AddError("Failed to wait for index to become ready.", err.Error())
+		}
+		return
+	}
+
+	// The index finished initializing, so it is no longer pending.
+	resp.Diagnostics.Append(private.SetKey(ctx, privateKeyPendingCreate, nil)...)
+}
+
+// denyNonEmptyDelete returns an error when the index still holds vectors, or when that
+// cannot be checked.
+func denyNonEmptyDelete(ctx context.Context, api client.ControlPlane, data *models.IndexResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	index, err := api.DescribeIndex(ctx, data.Name.ValueString())
+	if err != nil {
+		if !isNotFoundError(err) {
+			diags.AddError("Failed to describe index", err.Error())
+		}
+		return diags
+	}
+	if index.Host == "" {
+		// The index was never served, so it cannot hold vectors.
+		return diags
+	}
+
+	count, err := indexVectorCount(ctx, api, index.Host)
+	if err != nil {
+		diags.AddError("Failed to check whether index is empty",
+			fmt.Sprintf("Index %s is only deleted once it is known to hold no vectors: %s. "+
+				"Set force_destroy = true and apply it to delete the index regardless.", index.Name, err))
+		return diags
+	}
+	if count > 0 {
+		diags.AddError("Index is not empty",
+			fmt.Sprintf("Index %s still holds %d vectors, which would be lost. "+
+				"Delete them first, or set force_destroy = true and apply it before destroying the index.", index.Name, count))
+	}
+	return diags
+}
+
+// indexVectorCount returns the number of vectors in the index served at host.
+func indexVectorCount(ctx context.Context, api client.ControlPlane, host string) (uint32, error) {
+	index, err := api.Index(ctx, host)
+	if err != nil {
+		return 0, err
+	}
+	defer index.Close()
+
+	stats, err := index.DescribeIndexStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return stats.TotalVectorCount, nil
+}
+
+// indexSpecMismatches lists the attributes in which an existing index differs from the plan.
+func indexSpecMismatches(index *pinecone.Index, data *models.IndexResourceModel, spec *models.IndexSpecModel) []string {
+	var mismatches []string
+
+	if int64(index.Dimension) != data.Dimension.ValueInt64() {
+		mismatches = append(mismatches, fmt.Sprintf("dimension is %d, expected %d", index.Dimension, data.Dimension.ValueInt64()))
+	}
+	if string(index.Metric) != data.Metric.ValueString() {
+		mismatches = append(mismatches, fmt.Sprintf("metric is %s, expected %s", index.Metric, data.Metric.ValueString()))
+	}
+
+	var pod *pinecone.PodSpec
+	var serverless *pinecone.ServerlessSpec
+	if index.Spec != nil {
+		pod = index.Spec.Pod
+		serverless = index.Spec.Serverless
+	}
+
+	switch {
+	case spec.Pod != nil && pod == nil:
+		mismatches = append(mismatches, "spec is not pod-based")
+	case spec.Pod != nil:
+		if pod.Environment != spec.Pod.Environment.ValueString() {
+			mismatches = append(mismatches, fmt.Sprintf("spec.pod.environment is %s, expected %s", pod.Environment, spec.Pod.Environment.ValueString()))
+		}
+		if pod.PodType != spec.Pod.PodType.ValueString() {
+			mismatches = append(mismatches, fmt.Sprintf("spec.pod.pod_type is %s, expected %s", pod.PodType, spec.Pod.PodType.ValueString()))
+		}
+		if int64(pod.Replicas) != spec.Pod.Replicas.ValueInt64() {
+			mismatches = append(mismatches, fmt.Sprintf("spec.pod.replicas is %d, expected %d", pod.Replicas, spec.Pod.Replicas.ValueInt64()))
+		}
+		if int64(pod.ShardCount) != spec.Pod.ShardCount.ValueInt64() {
+			mismatches = append(mismatches, fmt.Sprintf("spec.pod.shards is %d, expected %d", pod.ShardCount, spec.Pod.ShardCount.ValueInt64()))
+		}
+	case spec.Serverless != nil && serverless == nil:
+		mismatches = append(mismatches, "spec is not serverless")
+	case spec.Serverless != nil:
+		if string(serverless.Cloud) != spec.Serverless.Cloud.ValueString() {
+			mismatches = append(mismatches, fmt.Sprintf("spec.serverless.cloud is %s, expected %s", serverless.Cloud, spec.Serverless.Cloud.ValueString()))
+		}
+		if serverless.Region != spec.Serverless.Region.ValueString() {
+			mismatches = append(mismatches, fmt.Sprintf("spec.serverless.region is %s, expected %s", serverless.Region, spec.Serverless.Region.ValueString()))
+		}
+	}
+
+	return mismatches
 }
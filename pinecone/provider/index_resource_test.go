@@ -4,11 +4,30 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
 func TestAccIndexResource_serverless(t *testing.T) {
@@ -171,3 +190,745 @@ resource "pinecone_index" "test" {
 }
 `, name, dimension)
 }
+
+// testIndexResourcePlan returns the plan Terraform would send for a new index with spec.
+func testIndexResourcePlan(t *testing.T, name string, spec models.IndexSpecModel) models.IndexResourceModel {
+	t.Helper()
+	ctx := context.Background()
+
+	if spec.Pod != nil {
+		spec.Pod.PodCount = types.Int64Unknown()
+		spec.Pod.MetadataConfig = types.ObjectUnknown(models.IndexMetadataConfigModel{}.AttrTypes())
+		if spec.Pod.Replicas.IsNull() {
+			spec.Pod.Replicas = types.Int64Value(1)
+		}
+		if spec.Pod.ShardCount.IsNull() {
+			spec.Pod.ShardCount = types.Int64Value(1)
+		}
+	}
+	specValue, diags := types.ObjectValueFrom(ctx, models.IndexSpecModel{}.AttrTypes(), spec)
+	if diags.HasError() {
+		t.Fatalf("invalid spec: %v", diags)
+	}
+
+	return models.IndexResourceModel{
+		Id:            types.StringUnknown(),
+		Name:          types.StringValue(name),
+		Dimension:     types.Int64Value(8),
+		Metric:        types.StringValue("cosine"),
+		Host:          types.StringUnknown(),
+		Spec:          specValue,
+		Status:        types.ObjectUnknown(models.IndexStatusModel{}.AttrTypes()),
+		AdoptExisting: types.BoolValue(false),
+		ForceDestroy:  types.BoolValue(false),
+		FinalSnapshot: types.ObjectNull(models.IndexFinalSnapshotModel{}.AttrTypes()),
+		WaitForReady:  types.BoolValue(true),
+		Timeouts:      nullTimeouts(),
+	}
+}
+
+func testServerlessSpec() models.IndexSpecModel {
+	return models.IndexSpecModel{
+		Serverless: &models.IndexServerlessSpecModel{
+			Cloud:  types.StringValue("aws"),
+			Region: types.StringValue("us-west-2"),
+		},
+	}
+}
+
+func testPodSpec() models.IndexSpecModel {
+	return models.IndexSpecModel{
+		Pod: &models.IndexPodSpecModel{
+			Environment:      types.StringValue("us-west4-gcp"),
+			PodType:          types.StringValue("s1.x1"),
+			Replicas:         types.Int64Value(2),
+			ShardCount:       types.Int64Null(),
+			SourceCollection: types.StringNull(),
+		},
+	}
+}
+
+func TestIndexResource_lifecycle(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	memory.ReadyAfter = 3
+	r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+
+	for name, spec := range map[string]models.IndexSpecModel{"serverless": testServerlessSpec(), "pod": testPodSpec()} {
+		resp := createTestResource(t, r, testIndexResourcePlan(t, name, spec))
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: create failed: %v", name, resp.Diagnostics)
+		}
+
+		var state models.IndexResourceModel
+		resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+		var status models.IndexStatusModel
+		resp.Diagnostics.Append(state.Status.As(ctx, &status, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("%s: invalid state: %v", name, resp.Diagnostics)
+		}
+		if state.Id.ValueString() != name || state.Host.ValueString() == "" || status.State.ValueString() != string(pinecone.Ready) {
+			t.Errorf("%s: unexpected state after create: %+v", name, state)
+		}
+		if got := memory.Calls(client.OpDescribeIndex); got < 3 {
+			t.Errorf("%s: expected the create to wait for the index to become ready, described %d times", name, got)
+		}
+
+		// Read refreshes the state from the index.
+		readResp := &fwresource.ReadResponse{State: resp.State}
+		r.Read(ctx, fwresource.ReadRequest{State: resp.State}, readResp)
+		if readResp.Diagnostics.HasError() || readResp.State.Raw.IsNull() {
+			t.Fatalf("%s: read failed: %v", name, readResp.Diagnostics)
+		}
+
+		deleteResp := &fwresource.DeleteResponse{State: resp.State}
+		r.Delete(ctx, fwresource.DeleteRequest{State: resp.State}, deleteResp)
+		if deleteResp.Diagnostics.HasError() {
+			t.Fatalf("%s: delete failed: %v", name, deleteResp.Diagnostics)
+		}
+		if _, err := memory.DescribeIndex(ctx, name); !isNotFoundError(err) {
+			t.Errorf("%s: expected the index to be deleted, got: %v", name, err)
+		}
+	}
+}
+
+func TestIndexResource_forceDestroy(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+	s := testResourceSchema(t, r)
+
+	created := createTestResource(t, r, testIndexResourcePlan(t, "full", testServerlessSpec()))
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+	var state models.IndexResourceModel
+	created.State.Get(ctx, &state)
+	memory.PutVectors(state.Host.ValueString(), "ns", &pinecone.Vector{Id: "a"}, &pinecone.Vector{Id: "b"})
+
+	deleteResp := &fwresource.DeleteResponse{State: created.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: created.State}, deleteResp)
+	if !deleteResp.Diagnostics.HasError() || !strings.Contains(deleteResp.Diagnostics.Errors()[0].Detail(), "2 vectors") {
+		t.Fatalf("expected the delete of a non-empty index to fail, got: %v", deleteResp.Diagnostics)
+	}
+	if memory.Calls(client.OpDeleteIndex) != 0 {
+		t.Fatal("expected the index not to be deleted")
+	}
+
+	state.ForceDestroy = types.BoolValue(true)
+	forced := newTestState(s)
+	forced.Set(ctx, &state)
+	deleteResp = &fwresource.DeleteResponse{State: forced}
+	r.Delete(ctx, fwresource.DeleteRequest{State: forced}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("expected force_destroy to delete the index, got: %v", deleteResp.Diagnostics)
+	}
+	if _, err := memory.DescribeIndex(ctx, "full"); !isNotFoundError(err) {
+		t.Errorf("expected the index to be deleted, got: %v", err)
+	}
+}
+
+func TestFinalSnapshotName(t *testing.T) {
+	now := time.Date(2024, 1, 31, 23, 59, 59, 0, time.FixedZone("CET", 3600))
+	if got := finalSnapshotName("{index}-final-{timestamp}", "products", now); got != "products-final-20240131-225959" {
+		t.Errorf("unexpected name %q", got)
+	}
+	if got := temporarySnapshotName("products-final", now); got != "products-final-20240131225959" {
+		t.Errorf("unexpected temporary name %q", got)
+	}
+	if got := temporarySnapshotName(strings.Repeat("a", 30)+"-final", now); len(got) > 45 {
+		t.Errorf("expected the temporary name to fit 45 characters, got %q", got)
+	}
+}
+
+func TestIndexResource_finalSnapshot(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+	s := testResourceSchema(t, r)
+
+	// createWithSnapshot creates an index holding two vectors, with a final snapshot.
+	createWithSnapshot := func(spec models.IndexSpecModel, retain bool) tfsdk.State {
+		t.Helper()
+		plan := testIndexResourcePlan(t, "snapshotted", spec)
+		plan.ForceDestroy = types.BoolValue(true)
+		plan.FinalSnapshot = types.ObjectValueMust(models.IndexFinalSnapshotModel{}.AttrTypes(), map[string]attr.Value{
+			"name":   types.StringValue("{index}-final"),
+			"retain": types.BoolValue(retain),
+		})
+		resp := createTestResource(t, r, plan)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("create failed: %v", resp.Diagnostics)
+		}
+		var state models.IndexResourceModel
+		resp.State.Get(ctx, &state)
+		memory.PutVectors(state.Host.ValueString(), "", &pinecone.Vector{Id: "a"}, &pinecone.Vector{Id: "b"})
+		return resp.State
+	}
+	destroy := func(state tfsdk.State) diag.Diagnostics {
+		resp := &fwresource.DeleteResponse{State: state}
+		r.Delete(ctx, fwresource.DeleteRequest{State: state}, resp)
+		return resp.Diagnostics
+	}
+
+	diags := destroy(createWithSnapshot(testPodSpec(), true))
+	if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags.Warnings()[0].Detail(), "snapshotted-final") {
+		t.Fatalf("expected a warning naming the snapshot, got: %v", diags)
+	}
+	collection, err := memory.DescribeCollection(ctx, "snapshotted-final")
+	if err != nil || *collection.VectorCount != 2 || collection.Status != pinecone.CollectionStatusReady {
+		t.Fatalf("expected a ready snapshot of 2 vectors, got %+v, %v", collection, err)
+	}
+
+	// A retained snapshot is not replaced, and the index is kept.
+	state := createWithSnapshot(testPodSpec(), true)
+	if diags := destroy(state); !diags.HasError() {
+		t.Fatal("expected the delete to fail when the snapshot exists")
+	}
+	if _, err := memory.DescribeIndex(ctx, "snapshotted"); err != nil {
+		t.Fatalf("expected the index to be kept, got: %v", err)
+	}
+
+	// Otherwise it is replaced, once a temporary copy of the index is ready.
+	memory.DeleteIndex(ctx, "snapshotted")
+	memory.Hook = func(op client.Operation, name string) error {
+		if op != client.OpDeleteCollection || name != "snapshotted-final" {
+			return nil
+		}
+		if collections, _ := memory.ListCollections(ctx); len(collections) != 2 || collections[1].Status != pinecone.CollectionStatusReady {
+			t.Errorf("expected a ready temporary snapshot before the earlier one is deleted, got %+v", collections)
+		}
+		return nil
+	}
+	if diags := destroy(createWithSnapshot(testPodSpec(), false)); diags.HasError() {
+		t.Fatalf("expected the snapshot to be replaced, got: %v", diags)
+	}
+	memory.Hook = nil
+	if collections, _ := memory.ListCollections(ctx); len(collections) != 1 || collections[0].Name != "snapshotted-final" {
+		t.Errorf("expected only the new snapshot to be left, got %+v", collections)
+	}
+
+	// A final snapshot keeps the vectors of an index that is not empty.
+	plan := testIndexResourcePlan(t, "kept", testPodSpec())
+	plan.FinalSnapshot = types.ObjectValueMust(models.IndexFinalSnapshotModel{}.AttrTypes(), map[string]attr.Value{
+		"name":   types.StringValue("{index}-final"),
+		"retain": types.BoolValue(true),
+	})
+	created := createTestResource(t, r, plan)
+	var kept models.IndexResourceModel
+	created.State.Get(ctx, &kept)
+	memory.PutVectors(kept.Host.ValueString(), "", &pinecone.Vector{Id: "a"})
+	if diags := destroy(created.State); diags.HasError() {
+		t.Fatalf("expected the snapshot to allow deleting a non-empty index, got: %v", diags)
+	}
+
+	// Serverless indexes are refused at plan time.
+	model := testIndexResourcePlan(t, "serverless", testServerlessSpec())
+	model.FinalSnapshot = types.ObjectValueMust(models.IndexFinalSnapshotModel{}.AttrTypes(), map[string]attr.Value{
+		"name":   types.StringValue("{index}-final"),
+		"retain": types.BoolValue(true),
+	})
+	serverlessPlan := newTestPlan(t, s, &model)
+	resp := &fwresource.ModifyPlanResponse{Plan: serverlessPlan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: serverlessPlan, State: newTestState(s)}, resp)
+	if got := attributePaths(resp.Diagnostics); len(got) != 1 || got[0] != "final_snapshot" {
+		t.Errorf("expected an error at final_snapshot, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIndexResource_projectApiKey(t *testing.T) {
+	ctx := context.Background()
+	defaultProject := client.NewMemory()
+	projects := map[string]*client.Memory{}
+	data := newTestProviderData(defaultProject)
+	data.Clients = newTestClientCache(projects)
+	r := newTestResource[*IndexResource](t, NewIndexResource, data)
+
+	plan := testIndexResourcePlan(t, "prod-index", testServerlessSpec())
+	plan.ProjectApiKey = types.StringValue("prod-key")
+	resp := createTestResource(t, r, plan)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", resp.Diagnostics)
+	}
+	if _, err := projects["prod-key"].DescribeIndex(ctx, "prod-index"); err != nil {
+		t.Errorf("expected the index to be created in the project of project_api_key: %v", err)
+	}
+	if got := defaultProject.Calls(client.OpCreateServerlessIndex); got != 0 {
+		t.Errorf("expected the provider's project not to be used, got %d creates", got)
+	}
+
+	deleteResp := &fwresource.DeleteResponse{State: resp.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: resp.State}, deleteResp)
+	if deleteResp.Diagnostics.HasError() {
+		t.Fatalf("delete failed: %v", deleteResp.Diagnostics)
+	}
+	if _, err := projects["prod-key"].DescribeIndex(ctx, "prod-index"); !isNotFoundError(err) {
+		t.Errorf("expected the index to be deleted from the project of project_api_key, got: %v", err)
+	}
+}
+
+func TestIndexResource_readOnly(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	writable := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+	created := createTestResource(t, writable, testIndexResourcePlan(t, "existing", testServerlessSpec()))
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+
+	data := newTestProviderData(memory)
+	data.ReadOnly = true
+	r := newTestResource[*IndexResource](t, NewIndexResource, data)
+	s := testResourceSchema(t, r)
+	model := testIndexResourcePlan(t, "new", testServerlessSpec())
+	plan := newTestPlan(t, s, &model)
+	calls := memory.Calls(client.OpCreateServerlessIndex) + memory.Calls(client.OpDeleteIndex)
+
+	cases := map[string]struct {
+		plan  tfsdk.Plan
+		state tfsdk.State
+		fails bool
+	}{
+		"create":    {plan, newTestState(s), true},
+		"delete":    {tfsdk.Plan(newTestState(s)), created.State, true},
+		"no change": {tfsdk.Plan{Schema: s, Raw: created.State.Raw}, created.State, false},
+	}
+	for name, c := range cases {
+		resp := &fwresource.ModifyPlanResponse{Plan: c.plan}
+		r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: c.plan, State: c.state}, resp)
+		if resp.Diagnostics.HasError() != c.fails {
+			t.Errorf("%s: expected plan to fail %t, got: %v", name, c.fails, resp.Diagnostics)
+		}
+	}
+
+	// Operations are refused even when the plan was not checked.
+	createResp := createTestResource(t, r, testIndexResourcePlan(t, "new", testServerlessSpec()))
+	deleteResp := &fwresource.DeleteResponse{State: created.State}
+	r.Delete(ctx, fwresource.DeleteRequest{State: created.State}, deleteResp)
+	if !createResp.Diagnostics.HasError() || !deleteResp.Diagnostics.HasError() {
+		t.Errorf("expected create and delete to fail, got: %v, %v", createResp.Diagnostics, deleteResp.Diagnostics)
+	}
+	if got := memory.Calls(client.OpCreateServerlessIndex) + memory.Calls(client.OpDeleteIndex); got != calls {
+		t.Errorf("expected no request to be sent, got %d", got-calls)
+	}
+}
+
+func TestIndexResource_policy(t *testing.T) {
+	ctx := context.Background()
+	data := newTestProviderData(client.NewMemory())
+	data.Policy = &IndexPolicy{AllowedRegions: []string{"us-east-1"}}
+	r := newTestResource[*IndexResource](t, NewIndexResource, data)
+	s := testResourceSchema(t, r)
+
+	model := testIndexResourcePlan(t, "new", testServerlessSpec())
+	plan := newTestPlan(t, s, &model)
+	resp := &fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: newTestState(s)}, resp)
+	if got := attributePaths(resp.Diagnostics); len(got) != 1 || got[0] != "spec.serverless.region" {
+		t.Errorf("expected the region to be refused, got: %v", resp.Diagnostics)
+	}
+
+	// Destroying an index that violates the policy is allowed.
+	destroy := tfsdk.Plan(newTestState(s))
+	resp = &fwresource.ModifyPlanResponse{Plan: destroy}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: destroy, State: newTestState(s)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("expected destroy to be allowed, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIndexResource_costEstimate(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	data := newTestProviderData(memory)
+	data.Costs = &CostEstimator{Prices: map[string]float64{"s1.x1": 0.1}, MonthlyIncreaseThreshold: 100}
+	r := newTestResource[*IndexResource](t, NewIndexResource, data)
+	s := testResourceSchema(t, r)
+
+	spec := testPodSpec()
+	spec.Pod.Replicas = types.Int64Value(1)
+	created := createTestResource(t, r, testIndexResourcePlan(t, "costs", spec))
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+	var state models.IndexResourceModel
+	created.State.Get(ctx, &state)
+	if state.EstimatedHourlyCost.ValueFloat64() != 0.1 || state.EstimatedMonthlyCost.ValueFloat64() != 73 {
+		t.Errorf("expected the costs of one pod in state, got %v and %v", state.EstimatedHourlyCost, state.EstimatedMonthlyCost)
+	}
+
+	// Going from 1 to 3 replicas costs $146 more a month, above the threshold.
+	spec = testPodSpec()
+	spec.Pod.Replicas = types.Int64Value(3)
+	model := testIndexResourcePlan(t, "costs", spec)
+	model.EstimatedHourlyCost = types.Float64Unknown()
+	model.EstimatedMonthlyCost = types.Float64Unknown()
+	plan := newTestPlan(t, s, &model)
+	resp := &fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: created.State}, resp)
+	// Changing the replicas also replaces the index, which is warned about as well.
+	if warnings := summaries(resp.Diagnostics.Warnings()); resp.Diagnostics.HasError() || fmt.Sprint(warnings) != "[Index will be replaced Estimated cost increase]" {
+		t.Errorf("expected a cost warning, got: %v", resp.Diagnostics)
+	}
+	var planned models.IndexResourceModel
+	resp.Plan.Get(ctx, &planned)
+	if planned.EstimatedMonthlyCost.ValueFloat64() != 219 {
+		t.Errorf("expected a planned monthly cost of 219, got %v", planned.EstimatedMonthlyCost)
+	}
+
+	// Refreshing the unchanged index neither warns nor changes the estimate.
+	plan = tfsdk.Plan{Schema: s, Raw: created.State.Raw}
+	resp = &fwresource.ModifyPlanResponse{Plan: plan}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: created.State}, resp)
+	if len(resp.Diagnostics) > 0 || !resp.Plan.Raw.Equal(created.State.Raw) {
+		t.Errorf("expected an unchanged plan without warnings, got: %v", resp.Diagnostics)
+	}
+}
+
+func TestIndexResource_replacementWarning(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+	s := testResourceSchema(t, r)
+
+	created := createTestResource(t, r, testIndexResourcePlan(t, "replaced", testServerlessSpec()))
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+	var state models.IndexResourceModel
+	created.State.Get(ctx, &state)
+	memory.PutVectors(state.Host.ValueString(), "", &pinecone.Vector{Id: "a"}, &pinecone.Vector{Id: "b"}, &pinecone.Vector{Id: "c"})
+
+	region := testServerlessSpec()
+	region.Serverless.Region = types.StringValue("us-east-1")
+
+	cases := map[string]struct {
+		spec   models.IndexSpecModel
+		change func(*models.IndexResourceModel)
+		want   []string
+	}{
+		"dimension and metric": {testServerlessSpec(), func(m *models.IndexResourceModel) {
+			m.Dimension = types.Int64Value(16)
+			m.Metric = types.StringValue("euclidean")
+		}, []string{"dimension", "metric"}},
+		"region":       {region, func(*models.IndexResourceModel) {}, []string{"spec.serverless.region"}},
+		"pod":          {testPodSpec(), func(*models.IndexResourceModel) {}, []string{"spec"}},
+		"unknown name": {testServerlessSpec(), func(m *models.IndexResourceModel) { m.Name = types.StringUnknown() }, nil},
+	}
+
+	for name, c := range cases {
+		planned := testIndexResourcePlan(t, "replaced", c.spec)
+		c.change(&planned)
+		plan := newTestPlan(t, s, &planned)
+		resp := &fwresource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: created.State}, resp)
+
+		var got []string
+		for _, d := range resp.Diagnostics.Warnings() {
+			if d, ok := d.(diag.DiagnosticWithPath); ok && d.Summary() == "Index will be replaced" {
+				got = append(got, d.Path().String())
+				if !strings.Contains(d.Detail(), "3 vectors") {
+					t.Errorf("%s: expected the vector count in %q", name, d.Detail())
+				}
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: expected warnings at %v, got %v", name, c.want, got)
+		}
+	}
+}
+
+func TestIndexResource_credentialsWarning(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	other := client.NewMemory()
+	other.PutIndex(&pinecone.Index{Name: "other", Host: "other-xyz789.svc.us-west4-gcp.pinecone.io"})
+	data := newTestProviderData(memory)
+	// The key same belongs to the project of the provider, other to project xyz789,
+	// and the project of empty cannot be resolved.
+	data.Clients = newTestClientCache(map[string]*client.Memory{"same": memory, "other": other})
+	r := newTestResource[*IndexResource](t, NewIndexResource, data)
+	s := testResourceSchema(t, r)
+
+	created := createTestResource(t, r, testIndexResourcePlan(t, "rotated", testServerlessSpec()))
+	if created.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", created.Diagnostics)
+	}
+
+	for key, want := range map[string]string{
+		"same":  "[Index credentials changed]",
+		"other": "[Index will be replaced]",
+		"empty": "[Index credentials changed]",
+	} {
+		planned := testIndexResourcePlan(t, "rotated", testServerlessSpec())
+		planned.ProjectApiKey = types.StringValue(key)
+		plan := newTestPlan(t, s, &planned)
+		resp := &fwresource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{Plan: plan, State: created.State}, resp)
+		if got := summaries(resp.Diagnostics); fmt.Sprint(got) != want {
+			t.Errorf("%s: expected %s, got %v", key, want, resp.Diagnostics)
+		}
+	}
+}
+
+// summaries returns the summary of every diagnostic in diags.
+func summaries(diags diag.Diagnostics) []string {
+	var got []string
+	for _, d := range diags {
+		got = append(got, d.Summary())
+	}
+	return got
+}
+
+// replacedAttributes returns the path of every attribute in attrs whose plan modifiers
+// require the resource to be replaced when its value changes.
+func replacedAttributes(t *testing.T, parent path.Path, attrs map[string]resourceschema.Attribute) []string {
+	ctx := context.Background()
+	// Conditional modifiers find nothing in the empty state and plan, and do not
+	// replace.
+	raw := tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})
+	state := tfsdk.State{Schema: resourceschema.Schema{}, Raw: raw}
+	plan := tfsdk.Plan{Schema: resourceschema.Schema{}, Raw: raw}
+
+	var paths []string
+	for name, a := range attrs {
+		p := parent.AtName(name)
+		replaced := false
+		switch a := a.(type) {
+		case resourceschema.StringAttribute:
+			req := planmodifier.StringRequest{Path: p, State: state, Plan: plan,
+				StateValue: types.StringValue("a"), PlanValue: types.StringValue("b"), ConfigValue: types.StringValue("b")}
+			for _, m := range a.PlanModifiers {
+				resp := &planmodifier.StringResponse{PlanValue: req.PlanValue}
+				m.PlanModifyString(ctx, req, resp)
+				replaced = replaced || resp.RequiresReplace
+			}
+		case resourceschema.Int64Attribute:
+			req := planmodifier.Int64Request{Path: p, State: state, Plan: plan,
+				StateValue: types.Int64Value(1), PlanValue: types.Int64Value(2), ConfigValue: types.Int64Value(2)}
+			for _, m := range a.PlanModifiers {
+				resp := &planmodifier.Int64Response{PlanValue: req.PlanValue}
+				m.PlanModifyInt64(ctx, req, resp)
+				replaced = replaced || resp.RequiresReplace
+			}
+		case resourceschema.SingleNestedAttribute:
+			paths = append(paths, replacedAttributes(t, p, a.Attributes)...)
+		}
+		if replaced {
+			paths = append(paths, p.String())
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestIndexReplacements_schema(t *testing.T) {
+	ctx := context.Background()
+	s := testResourceSchema(t, NewIndexResource())
+
+	// Changing every attribute of an index of either kind warns about every attribute
+	// that requires a replacement.
+	changed := func(spec models.IndexSpecModel) models.IndexResourceModel {
+		m := testIndexResourcePlan(t, "other", spec)
+		m.Dimension = types.Int64Value(16)
+		m.Metric = types.StringValue("euclidean")
+		m.ProjectApiKey = types.StringValue("other-key")
+		m.Profile = types.StringValue("other")
+		return m
+	}
+	pod := testPodSpec()
+	pod.Pod.Environment = types.StringValue("us-east1-gcp")
+	pod.Pod.Replicas = types.Int64Value(3)
+	pod.Pod.ShardCount = types.Int64Value(2)
+	pod.Pod.PodType = types.StringValue("p1.x1")
+	pod.Pod.SourceCollection = types.StringValue("backup")
+	serverless := testServerlessSpec()
+	serverless.Serverless.Cloud = types.StringValue("gcp")
+	serverless.Serverless.Region = types.StringValue("us-central1")
+
+	var got []string
+	for _, c := range []struct{ prior, planned models.IndexSpecModel }{{testPodSpec(), pod}, {testServerlessSpec(), serverless}} {
+		prior := testIndexResourcePlan(t, "replaced", c.prior)
+		planned := changed(c.planned)
+		replacements, diags := indexReplacements(ctx, &prior, &planned)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		for _, r := range replacements {
+			if !slices.Contains(got, r.path.String()) {
+				got = append(got, r.path.String())
+			}
+			if strings.Contains(r.from+r.to, "key") {
+				t.Errorf("expected the API key not to be shown, got %s to %s", r.from, r.to)
+			}
+		}
+	}
+	sort.Strings(got)
+
+	if want := replacedAttributes(t, path.Empty(), s.Attributes); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("expected replacement warnings for %v, got %v", want, got)
+	}
+}
+
+func TestIndexResource_Read_drift(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+
+	resp := createTestResource(t, r, testIndexResourcePlan(t, "drift", testServerlessSpec()))
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("create failed: %v", resp.Diagnostics)
+	}
+
+	if err := memory.DeleteIndex(ctx, "drift"); err != nil {
+		t.Fatal(err)
+	}
+	readResp := &fwresource.ReadResponse{State: resp.State}
+	r.Read(ctx, fwresource.ReadRequest{State: resp.State}, readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("read failed: %v", readResp.Diagnostics)
+	}
+	if !readResp.State.Raw.IsNull() {
+		t.Error("expected an index deleted outside of Terraform to be removed from state")
+	}
+}
+
+func TestIndexResource_Create_errors(t *testing.T) {
+	cases := map[string]struct {
+		hook    func(memory *client.Memory) func(op client.Operation, name string) error
+		summary string
+	}{
+		"create fails": {
+			hook: func(memory *client.Memory) func(op client.Operation, name string) error {
+				return func(op client.Operation, name string) error {
+					if op == client.OpCreateServerlessIndex {
+						return errors.New("failed to create index: Invalid region")
+					}
+					return nil
+				}
+			},
+			summary: "Failed to create serverless index",
+		},
+		"initialization fails": {
+			hook: func(memory *client.Memory) func(op client.Operation, name string) error {
+				return func(op client.Operation, name string) error {
+					if op == client.OpDescribeIndex {
+						memory.PutIndex(&pinecone.Index{
+							Name:      name,
+							Dimension: 8,
+							Metric:    pinecone.Cosine,
+							Spec:      &pinecone.IndexSpec{Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-west-2"}},
+							Status:    &pinecone.IndexStatus{State: pinecone.InitializationFailed},
+						})
+					}
+					return nil
+				}
+			},
+			summary: "Failed to wait for index to become ready.",
+		},
+		"describe fails": {
+			hook: func(memory *client.Memory) func(op client.Operation, name string) error {
+				return func(op client.Operation, name string) error {
+					if op == client.OpDescribeIndex {
+						return errors.New("unexpected status code: 500")
+					}
+					return nil
+				}
+			},
+			summary: "Failed to wait for index to become ready.",
+		},
+	}
+
+	for name, c := range cases {
+		memory := client.NewMemory()
+		memory.ReadyAfter = 100
+		memory.Hook = c.hook(memory)
+		r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+
+		resp := createTestResource(t, r, testIndexResourcePlan(t, "failing", testServerlessSpec()))
+		if !resp.Diagnostics.HasError() {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if got := resp.Diagnostics.Errors()[0].Summary(); got != c.summary {
+			t.Errorf("%s: expected error %q, got %q", name, c.summary, got)
+		}
+	}
+}
+
+func TestIndexResource_Create_timeoutResumes(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	memory.ReadyAfter = 1 << 30
+	data := newTestProviderData(memory)
+	data.Timeouts.Create = 20 * time.Millisecond
+	r := newTestResource[*IndexResource](t, NewIndexResource, data)
+
+	// The framework keeps private state, which the test stands in for.
+	private := testPrivateState{}
+	s := testResourceSchema(t, r)
+	plan := newTestPlan(t, s, testIndexResourcePlan(t, "slow", testServerlessSpec()))
+	resp := &fwresource.CreateResponse{State: newTestState(s)}
+	r.create(ctx, fwresource.CreateRequest{Plan: plan}, resp, private)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected a timed out create to only warn, got: %v", resp.Diagnostics)
+	}
+	if resp.Diagnostics.WarningsCount() != 1 || resp.State.Raw.IsNull() {
+		t.Fatalf("expected the initializing index to be saved with a warning, got: %v", resp.Diagnostics)
+	}
+	if pending, _ := private.GetKey(ctx, privateKeyPendingCreate); len(pending) == 0 {
+		t.Fatal("expected the index to be marked as pending")
+	}
+
+	// The next refresh resumes waiting and clears the mark once the index is ready.
+	memory.ReadyAfter = 0
+	readResp := &fwresource.ReadResponse{State: resp.State}
+	r.read(ctx, fwresource.ReadRequest{State: resp.State}, readResp, private)
+	if readResp.Diagnostics.HasError() {
+		t.Fatalf("read failed: %v", readResp.Diagnostics)
+	}
+	if pending, _ := private.GetKey(ctx, privateKeyPendingCreate); len(pending) != 0 {
+		t.Error("expected the pending mark to be cleared")
+	}
+}
+
+func TestIndexResource_Create_adoptExisting(t *testing.T) {
+	existing := &pinecone.Index{
+		Name:      "existing",
+		Dimension: 8,
+		Metric:    pinecone.Cosine,
+		Host:      "existing-abc123.svc.aped-4627-b74a.pinecone.io",
+		Spec:      &pinecone.IndexSpec{Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-west-2"}},
+		Status:    &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	}
+
+	cases := map[string]struct {
+		adopt     bool
+		dimension int64
+		err       string
+	}{
+		"adopted":      {adopt: true, dimension: 8},
+		"not adopted":  {adopt: false, dimension: 8, err: "already exists"},
+		"incompatible": {adopt: true, dimension: 16, err: "cannot be adopted because its dimension is 8, expected 16"},
+	}
+
+	for name, c := range cases {
+		memory := client.NewMemory()
+		memory.PutIndex(existing)
+		r := newTestResource[*IndexResource](t, NewIndexResource, newTestProviderData(memory))
+
+		plan := testIndexResourcePlan(t, "existing", testServerlessSpec())
+		plan.AdoptExisting = types.BoolValue(c.adopt)
+		plan.Dimension = types.Int64Value(c.dimension)
+		resp := createTestResource(t, r, plan)
+
+		switch {
+		case c.err == "" && resp.Diagnostics.HasError():
+			t.Errorf("%s: unexpected error: %v", name, resp.Diagnostics)
+		case c.err != "" && !resp.Diagnostics.HasError():
+			t.Errorf("%s: expected an error", name)
+		case c.err != "" && !strings.Contains(resp.Diagnostics.Errors()[0].Detail(), c.err):
+			t.Errorf("%s: expected error containing %q, got %q", name, c.err, resp.Diagnostics.Errors()[0].Detail())
+		}
+	}
+}
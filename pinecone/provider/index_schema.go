@@ -0,0 +1,402 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	resourceschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type attributeKind int
+
+const (
+	stringKind attributeKind = iota
+	int64Kind
+	float64Kind
+	boolKind
+	stringListKind
+	objectKind
+)
+
+// indexAttribute defines an attribute of an index once. The pinecone_index resource,
+// the pinecone_index data source and the indexes of the pinecone_indexes data source
+// are all generated from these definitions, so a new attribute only has to be added
+// to indexAttributes (and the models) to show up everywhere.
+type indexAttribute struct {
+	kind        attributeKind
+	description string
+	attributes  map[string]indexAttribute
+
+	// How the attribute behaves in the resource. Data sources compute every
+	// attribute, except for the name of the pinecone_index data source.
+	required bool
+	optional bool
+	computed bool
+
+	// resourceOnly attributes configure how the resource is managed, or depend on
+	// provider settings, and are not exposed by the data sources.
+	resourceOnly bool
+
+	stringDefault       *string
+	int64Default        *int64
+	boolDefault         *bool
+	stringValidators    []validator.String
+	int64Validators     []validator.Int64
+	stringPlanModifiers []planmodifier.String
+	int64PlanModifiers  []planmodifier.Int64
+}
+
+func indexAttributes() map[string]indexAttribute {
+	defaultMetric := "cosine"
+	defaultReplicas := int64(1)
+	defaultShards := int64(1)
+	defaultAdoptExisting := false
+	defaultWaitForReady := true
+	defaultForceDestroy := false
+	defaultRetainSnapshot := true
+
+	return map[string]indexAttribute{
+		"id": {
+			kind:        stringKind,
+			description: "Index identifier",
+			computed:    true,
+		},
+		"name": {
+			kind:                stringKind,
+			description:         "The name of the index. The maximum length is 45 characters.",
+			required:            true,
+			stringValidators:    []validator.String{stringvalidator.LengthAtMost(45)},
+			stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+		},
+		"dimension": {
+			kind:               int64Kind,
+			description:        "The dimensions of the vectors to be inserted in the index.",
+			required:           true,
+			int64Validators:    []validator.Int64{int64validator.AtLeast(1)},
+			int64PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+		},
+		"metric": {
+			kind:                stringKind,
+			description:         "The distance metric to be used for similarity search. You can use 'euclidean', 'cosine', or 'dotproduct'.",
+			optional:            true,
+			computed:            true,
+			stringDefault:       &defaultMetric,
+			stringValidators:    []validator.String{stringvalidator.OneOf("euclidean", "cosine", "dotproduct")},
+			stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+		},
+		"host": {
+			kind:        stringKind,
+			description: "The URL address where the index is hosted.",
+			computed:    true,
+		},
+		"adopt_existing": {
+			kind: boolKind,
+			description: "Whether to adopt an existing index with the same name instead of failing to create it. " +
+				"The existing index is only adopted when its dimension, metric and spec match the configuration. Defaults to false.",
+			optional:     true,
+			computed:     true,
+			resourceOnly: true,
+			boolDefault:  &defaultAdoptExisting,
+		},
+		"wait_for_ready": {
+			kind:         boolKind,
+			description:  "Whether to wait for the index to become ready after it is created. Defaults to true.",
+			optional:     true,
+			computed:     true,
+			resourceOnly: true,
+			boolDefault:  &defaultWaitForReady,
+		},
+		"estimated_hourly_cost": {
+			kind: float64Kind,
+			description: "Estimated cost in USD of running a pod index for an hour: the price of its pod type times its replicas and shards. " +
+				"Prices come from a table built into the provider, which the provider's `cost_estimate` block can override. " +
+				"Null for serverless indexes, which are billed by usage, and for pod types without a price.",
+			computed:     true,
+			resourceOnly: true,
+		},
+		"estimated_monthly_cost": {
+			kind:         float64Kind,
+			description:  "Estimated cost in USD of running a pod index for a month of 730 hours. See `estimated_hourly_cost`.",
+			computed:     true,
+			resourceOnly: true,
+		},
+		"force_destroy": {
+			kind: boolKind,
+			description: "Whether to delete the index even though it still holds vectors. When false, destroying or replacing an index " +
+				"that is not empty fails, so that its vectors are not lost by accident, unless a `final_snapshot` of it is taken. " +
+				"The setting must be applied before the index is destroyed to take effect. Defaults to false.",
+			optional:     true,
+			computed:     true,
+			resourceOnly: true,
+			boolDefault:  &defaultForceDestroy,
+		},
+		"final_snapshot": {
+			kind: objectKind,
+			description: "Takes a snapshot of a pod index before it is destroyed or replaced: a collection created from the index, " +
+				"which a new index can be created from with `source_collection`. The delete waits for the collection to be ready.",
+			optional:     true,
+			resourceOnly: true,
+			attributes: map[string]indexAttribute{
+				"name": {
+					kind: stringKind,
+					description: "Name of the collection. `{index}` is replaced with the name of the index and `{timestamp}` " +
+						"with the time of the delete in UTC, such as 20240131-235959.",
+					required:         true,
+					stringValidators: []validator.String{stringvalidator.LengthAtLeast(1)},
+				},
+				"retain": {
+					kind: boolKind,
+					description: "Whether an existing collection with the same name is kept. When true, the delete fails rather " +
+						"than replace it. When false, it is replaced by the new snapshot, which keeps only the latest " +
+						"snapshot of a name without `{timestamp}`. The index is first snapshotted under a temporary name, " +
+						"so that a copy of it exists until the replacement is ready. Defaults to true.",
+					optional:    true,
+					computed:    true,
+					boolDefault: &defaultRetainSnapshot,
+				},
+			},
+		},
+		"spec": {
+			kind:        objectKind,
+			description: "The spec of the index. Exactly one of pod or serverless must be set.",
+			required:    true,
+			attributes: map[string]indexAttribute{
+				"pod": {
+					kind:        objectKind,
+					description: "Configuration needed to deploy a pod-based index.",
+					optional:    true,
+					attributes: map[string]indexAttribute{
+						"environment": {
+							kind:                stringKind,
+							description:         "The environment where the index is hosted.",
+							required:            true,
+							stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+						},
+						"replicas": {
+							kind:               int64Kind,
+							description:        "The number of replicas. Replicas duplicate your index. They provide higher availability and throughput. Replicas can be scaled up or down as your needs change.",
+							optional:           true,
+							computed:           true,
+							int64Default:       &defaultReplicas,
+							int64PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+						},
+						"shards": {
+							kind:               int64Kind,
+							description:        "The number of shards. Shards split your data across multiple pods so you can fit more data into an index.",
+							optional:           true,
+							computed:           true,
+							int64Default:       &defaultShards,
+							int64PlanModifiers: []planmodifier.Int64{int64planmodifier.RequiresReplace()},
+						},
+						"pod_type": {
+							kind:                stringKind,
+							description:         "The type of pod to use. One of s1, p1, or p2 appended with . and one of x1, x2, x4, or x8.",
+							required:            true,
+							stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+						},
+						"pods": {
+							kind:        int64Kind,
+							description: "The number of pods to be used in the index. This should be equal to shards x replicas.",
+							computed:    true,
+						},
+						"metadata_config": {
+							kind:        objectKind,
+							description: "Configuration for the behavior of Pinecone's internal metadata index. By default, all metadata is indexed; when metadata_config is present, only specified metadata fields are indexed. These configurations are only valid for use with pod-based indexes.",
+							optional:    true,
+							computed:    true,
+							attributes: map[string]indexAttribute{
+								"indexed": {
+									kind:        stringListKind,
+									description: "The indexed fields.",
+									required:    true,
+								},
+							},
+						},
+						"source_collection": {
+							kind:                stringKind,
+							description:         "The name of the collection to create an index from.",
+							optional:            true,
+							stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+						},
+					},
+				},
+				"serverless": {
+					kind:        objectKind,
+					description: "Configuration needed to deploy a serverless index.",
+					optional:    true,
+					attributes: map[string]indexAttribute{
+						"cloud": {
+							kind:                stringKind,
+							description:         "The public cloud where the index is hosted. One of 'gcp', 'aws' or 'azure'.",
+							required:            true,
+							stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+						},
+						"region": {
+							kind:                stringKind,
+							description:         "The region where the index is hosted.",
+							required:            true,
+							stringPlanModifiers: []planmodifier.String{stringplanmodifier.RequiresReplace()},
+						},
+					},
+				},
+			},
+		},
+		"status": {
+			kind:        objectKind,
+			description: "The status of the index.",
+			computed:    true,
+			attributes: map[string]indexAttribute{
+				"ready": {
+					kind:        boolKind,
+					description: "Whether the index is ready to serve requests.",
+					computed:    true,
+				},
+				"state": {
+					kind:        stringKind,
+					description: "The state of the index. One of Initializing, InitializationFailed, ScalingUp, ScalingDown, ScalingUpPodSize, ScalingDownPodSize, Upgrading, Terminating or Ready.",
+					computed:    true,
+				},
+			},
+		},
+	}
+}
+
+// indexResourceAttributes returns the attributes of the pinecone_index resource.
+func indexResourceAttributes() map[string]resourceschema.Attribute {
+	return resourceAttributes(indexAttributes())
+}
+
+// indexDataSourceAttributes returns the attributes of the pinecone_index data source,
+// which looks an index up by its name.
+func indexDataSourceAttributes() map[string]datasourceschema.Attribute {
+	attrs := dataSourceAttributes(indexAttributes())
+	attrs["name"] = datasourceschema.StringAttribute{
+		MarkdownDescription: indexAttributes()["name"].description,
+		Required:            true,
+	}
+	return attrs
+}
+
+// indexListAttributes returns the attributes of each index listed by the
+// pinecone_indexes data source.
+func indexListAttributes() map[string]datasourceschema.Attribute {
+	attrs := dataSourceAttributes(indexAttributes())
+	delete(attrs, "id")
+	return attrs
+}
+
+func resourceAttributes(defs map[string]indexAttribute) map[string]resourceschema.Attribute {
+	attrs := make(map[string]resourceschema.Attribute, len(defs))
+	for name, def := range defs {
+		attrs[name] = def.resourceAttribute()
+	}
+	return attrs
+}
+
+func dataSourceAttributes(defs map[string]indexAttribute) map[string]datasourceschema.Attribute {
+	attrs := make(map[string]datasourceschema.Attribute, len(defs))
+	for name, def := range defs {
+		if def.resourceOnly {
+			continue
+		}
+		attrs[name] = def.dataSourceAttribute()
+	}
+	return attrs
+}
+
+func (a indexAttribute) resourceAttribute() resourceschema.Attribute {
+	switch a.kind {
+	case stringKind:
+		attr := resourceschema.StringAttribute{
+			MarkdownDescription: a.description,
+			Required:            a.required,
+			Optional:            a.optional,
+			Computed:            a.computed,
+			Validators:          a.stringValidators,
+			PlanModifiers:       a.stringPlanModifiers,
+		}
+		if a.stringDefault != nil {
+			attr.Default = stringdefault.StaticString(*a.stringDefault)
+		}
+		return attr
+	case int64Kind:
+		attr := resourceschema.Int64Attribute{
+			MarkdownDescription: a.description,
+			Required:            a.required,
+			Optional:            a.optional,
+			Computed:            a.computed,
+			Validators:          a.int64Validators,
+			PlanModifiers:       a.int64PlanModifiers,
+		}
+		if a.int64Default != nil {
+			attr.Default = int64default.StaticInt64(*a.int64Default)
+		}
+		return attr
+	case float64Kind:
+		return resourceschema.Float64Attribute{
+			MarkdownDescription: a.description,
+			Required:            a.required,
+			Optional:            a.optional,
+			Computed:            a.computed,
+		}
+	case boolKind:
+		attr := resourceschema.BoolAttribute{
+			MarkdownDescription: a.description,
+			Required:            a.required,
+			Optional:            a.optional,
+			Computed:            a.computed,
+		}
+		if a.boolDefault != nil {
+			attr.Default = booldefault.StaticBool(*a.boolDefault)
+		}
+		return attr
+	case stringListKind:
+		return resourceschema.ListAttribute{
+			MarkdownDescription: a.description,
+			Required:            a.required,
+			Optional:            a.optional,
+			Computed:            a.computed,
+			ElementType:         types.StringType,
+		}
+	default:
+		return resourceschema.SingleNestedAttribute{
+			MarkdownDescription: a.description,
+			Required:            a.required,
+			Optional:            a.optional,
+			Computed:            a.computed,
+			Attributes:          resourceAttributes(a.attributes),
+		}
+	}
+}
+
+func (a indexAttribute) dataSourceAttribute() datasourceschema.Attribute {
+	switch a.kind {
+	case stringKind:
+		return datasourceschema.StringAttribute{MarkdownDescription: a.description, Computed: true}
+	case int64Kind:
+		return datasourceschema.Int64Attribute{MarkdownDescription: a.description, Computed: true}
+	case float64Kind:
+		return datasourceschema.Float64Attribute{MarkdownDescription: a.description, Computed: true}
+	case boolKind:
+		return datasourceschema.BoolAttribute{MarkdownDescription: a.description, Computed: true}
+	case stringListKind:
+		return datasourceschema.ListAttribute{MarkdownDescription: a.description, Computed: true, ElementType: types.StringType}
+	default:
+		return datasourceschema.SingleNestedAttribute{
+			MarkdownDescription: a.description,
+			Computed:            true,
+			Attributes:          dataSourceAttributes(a.attributes),
+		}
+	}
+}
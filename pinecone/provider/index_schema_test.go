@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	datasourceschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+func testIndexes() map[string]*pinecone.Index {
+	sourceCollection := "movies"
+	return map[string]*pinecone.Index{
+		"pod": {
+			Name:      "pod-index",
+			Dimension: 1536,
+			Metric:    pinecone.Euclidean,
+			Host:      "pod-index-abc123.svc.us-west4-gcp.pinecone.io",
+			Spec: &pinecone.IndexSpec{
+				Pod: &pinecone.PodSpec{
+					Environment:      "us-west4-gcp",
+					PodType:          "p1.x1",
+					PodCount:         2,
+					Replicas:         2,
+					ShardCount:       1,
+					SourceCollection: &sourceCollection,
+					MetadataConfig:   &pinecone.PodSpecMetadataConfig{Indexed: &[]string{"genre", "year"}},
+				},
+			},
+			Status: &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+		},
+		"serverless": {
+			Name:      "serverless-index",
+			Dimension: 8,
+			Metric:    pinecone.Cosine,
+			Host:      "serverless-index-abc123.svc.aped-4627-b74a.pinecone.io",
+			Spec: &pinecone.IndexSpec{
+				Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-west-2"},
+			},
+			Status: &pinecone.IndexStatus{State: pinecone.Initializing},
+		},
+		"no status": {
+			Name:      "pending-index",
+			Dimension: 8,
+			Metric:    pinecone.Dotproduct,
+			Spec: &pinecone.IndexSpec{
+				Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Gcp, Region: "us-central1"},
+			},
+		},
+	}
+}
+
+// roundTrip stores in into a state of the given schema, reads it back into out and
+// fails the test if the two differ.
+func roundTrip(t *testing.T, name string, schema tfsdk.State, in, out interface{}) {
+	t.Helper()
+	ctx := context.Background()
+
+	schema.Raw = tftypes.NewValue(schema.Schema.Type().TerraformType(ctx), nil)
+	if diags := schema.Set(ctx, in); diags.HasError() {
+		t.Fatalf("%s: failed to set state: %v", name, diags)
+	}
+	if diags := schema.Get(ctx, out); diags.HasError() {
+		t.Fatalf("%s: failed to get state: %v", name, diags)
+	}
+	if !reflect.DeepEqual(reflect.ValueOf(in).Elem().Interface(), reflect.ValueOf(out).Elem().Interface()) {
+		t.Errorf("%s: round trip changed the model\nexpected: %+v\ngot:      %+v", name, in, out)
+	}
+}
+
+func TestIndexResourceModel_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	resp := &resource.SchemaResponse{}
+	NewIndexResource().Schema(ctx, resource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("invalid schema: %v", resp.Diagnostics)
+	}
+
+	for name, index := range testIndexes() {
+		in := models.IndexResourceModel{
+			AdoptExisting: types.BoolValue(false),
+			ForceDestroy:  types.BoolValue(false),
+			FinalSnapshot: types.ObjectNull(models.IndexFinalSnapshotModel{}.AttrTypes()),
+			WaitForReady:  types.BoolValue(true),
+			Timeouts:      nullTimeouts(),
+		}
+		if diags := in.Read(ctx, index); diags.HasError() {
+			t.Fatalf("%s: failed to read index: %v", name, diags)
+		}
+		if in.Id.ValueString() != index.Name {
+			t.Errorf("%s: expected id %q, got %q", name, index.Name, in.Id.ValueString())
+		}
+
+		var out models.IndexResourceModel
+		roundTrip(t, name, tfsdk.State{Schema: resp.Schema}, &in, &out)
+	}
+}
+
+func TestIndexDatasourceModel_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	resp := &datasource.SchemaResponse{}
+	NewIndexDataSource().Schema(ctx, datasource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("invalid schema: %v", resp.Diagnostics)
+	}
+
+	for name, index := range testIndexes() {
+		var in, out models.IndexDatasourceModel
+		if diags := in.Read(ctx, index); diags.HasError() {
+			t.Fatalf("%s: failed to read index: %v", name, diags)
+		}
+		roundTrip(t, name, tfsdk.State{Schema: resp.Schema}, &in, &out)
+	}
+}
+
+func TestIndexesDataSourceModel_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	resp := &datasource.SchemaResponse{}
+	NewIndexesDataSource().Schema(ctx, datasource.SchemaRequest{}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("invalid schema: %v", resp.Diagnostics)
+	}
+
+	in := models.IndexesDataSourceModel{
+		Names:      types.ListNull(types.StringType),
+		NameRegex:  types.StringNull(),
+		NamePrefix: types.StringNull(),
+		SpecType:   types.StringNull(),
+		Cloud:      types.StringNull(),
+		Region:     types.StringNull(),
+		ReadyOnly:  types.BoolNull(),
+		Id:         types.StringValue("id"),
+	}
+	for name, index := range testIndexes() {
+		var model models.IndexModel
+		if diags := model.Read(ctx, index); diags.HasError() {
+			t.Fatalf("%s: failed to read index: %v", name, diags)
+		}
+		in.Indexes = append(in.Indexes, model)
+	}
+
+	var out models.IndexesDataSourceModel
+	roundTrip(t, "indexes", tfsdk.State{Schema: resp.Schema}, &in, &out)
+}
+
+func TestIndexSchema_DataSourcesMatchResource(t *testing.T) {
+	resource := indexResourceAttributes()
+	single := indexDataSourceAttributes()
+	list := indexListAttributes()
+
+	for name, def := range indexAttributes() {
+		if _, ok := resource[name]; !ok {
+			t.Errorf("resource is missing %q", name)
+		}
+		_, inSingle := single[name]
+		_, inList := list[name]
+		if def.resourceOnly {
+			if inSingle || inList {
+				t.Errorf("resource-only attribute %q is exposed by a data source", name)
+			}
+			continue
+		}
+		if !inSingle {
+			t.Errorf("pinecone_index data source is missing %q", name)
+		}
+		if !inList && name != "id" {
+			t.Errorf("pinecone_indexes data source is missing %q", name)
+		}
+	}
+
+	if !single["name"].IsRequired() {
+		t.Error("expected name to be required by the pinecone_index data source")
+	}
+	if _, ok := list["id"]; ok {
+		t.Error("expected the indexes of the pinecone_indexes data source not to have an id")
+	}
+	spec := single["spec"].(datasourceschema.SingleNestedAttribute)
+	if !spec.Attributes["pod"].IsComputed() || spec.Attributes["pod"].IsOptional() {
+		t.Error("expected spec.pod to be computed only in the data source")
+	}
+}
+
+func TestNewIndexServerlessSpec(t *testing.T) {
+	spec := models.NewIndexServerlessSpec(&models.IndexServerlessSpecModel{
+		Cloud:  types.StringValue("aws"),
+		Region: types.StringValue("us-west-2"),
+	})
+	if spec.Cloud != pinecone.Aws || spec.Region != "us-west-2" {
+		t.Errorf("unexpected serverless spec: %+v", spec)
+	}
+}
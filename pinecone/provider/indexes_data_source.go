@@ -5,13 +5,20 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"strconv"
-	"time"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/go-pinecone/pinecone"
 	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
@@ -41,112 +48,48 @@ func (d *IndexesDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				MarkdownDescription: "List of the indexes in your project",
 				Computed:            true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"name": schema.StringAttribute{
-							MarkdownDescription: "Index name",
-							Computed:            true,
-						},
-						"dimension": schema.Int64Attribute{
-							MarkdownDescription: "Index dimension",
-							Computed:            true,
-						},
-						"metric": schema.StringAttribute{
-							MarkdownDescription: "Index metric",
-							Computed:            true,
-						},
-						"host": schema.StringAttribute{
-							MarkdownDescription: "The URL address where the index is hosted.",
-							Computed:            true,
-						},
-						"spec": schema.SingleNestedAttribute{
-							Description: "Spec",
-							Optional:    true,
-							Computed:    true,
-							Attributes: map[string]schema.Attribute{
-								"pod": schema.SingleNestedAttribute{
-									Description: "Configuration needed to deploy a pod-based index.",
-									Optional:    true,
-									Computed:    true,
-									Attributes: map[string]schema.Attribute{
-										"environment": schema.StringAttribute{
-											MarkdownDescription: "The environment where the index is hosted.",
-											Computed:            true,
-										},
-										"replicas": schema.Int64Attribute{
-											MarkdownDescription: "The number of replicas. Replicas duplicate your index. They provide higher availability and throughput. Replicas can be scaled up or down as your needs change.",
-											Computed:            true,
-										},
-										"shards": schema.Int64Attribute{
-											MarkdownDescription: "The number of shards. Shards split your data across multiple pods so you can fit more data into an index.",
-											Computed:            true,
-										},
-										"pod_type": schema.StringAttribute{
-											MarkdownDescription: "The type of pod to use. One of s1, p1, or p2 appended with . and one of x1, x2, x4, or x8.",
-											Computed:            true,
-										},
-										"pods": schema.Int64Attribute{
-											MarkdownDescription: "The number of pods to be used in the index. This should be equal to shards x replicas.'",
-											Computed:            true,
-										},
-										"metadata_config": schema.SingleNestedAttribute{
-											Description: "Configuration for the behavior of Pinecone's internal metadata index. By default, all metadata is indexed; when metadata_config is present, only specified metadata fields are indexed. These configurations are only valid for use with pod-based indexes.",
-											Optional:    true,
-											Computed:    true,
-											Attributes: map[string]schema.Attribute{
-												"indexed": schema.ListAttribute{
-													Description: "The indexed fields.",
-													Computed:    true,
-													ElementType: types.StringType,
-												},
-											},
-										},
-										"source_collection": schema.StringAttribute{
-											MarkdownDescription: "The name of the collection to create an index from.",
-											Computed:            true,
-										},
-									},
-								},
-								"serverless": schema.SingleNestedAttribute{
-									Description: "Configuration needed to deploy a serverless index.",
-									Optional:    true,
-									Computed:    true,
-									Attributes: map[string]schema.Attribute{
-										"cloud": schema.StringAttribute{
-											Description: "Ready.",
-											Computed:    true,
-										},
-										"region": schema.StringAttribute{
-											MarkdownDescription: "Initializing InitializationFailed ScalingUp ScalingDown ScalingUpPodSize ScalingDownPodSize Upgrading Terminating Ready",
-											Computed:            true,
-										},
-									},
-								},
-							},
-						},
-						"status": schema.SingleNestedAttribute{
-							Description: "Configuration for the behavior of Pinecone's internal metadata index. By default, all metadata is indexed; when metadata_config is present, only specified metadata fields are indexed. To specify metadata fields to index, provide an array of the following form: [example_metadata_field]",
-							Optional:    true,
-							Computed:    true,
-							Attributes: map[string]schema.Attribute{
-								"ready": schema.BoolAttribute{
-									Description: "Ready.",
-									Computed:    true,
-								},
-								"state": schema.StringAttribute{
-									MarkdownDescription: "Initializing InitializationFailed ScalingUp ScalingDown ScalingUpPodSize ScalingDownPodSize Upgrading Terminating Ready",
-									Computed:            true,
-								},
-							},
-						},
-					},
+					Attributes: indexListAttributes(),
 				},
 			},
+			"names": schema.ListAttribute{
+				MarkdownDescription: "Names of the indexes matching the filters, sorted alphabetically.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"name_regex": schema.StringAttribute{
+				MarkdownDescription: "Only return indexes whose name matches this [regular expression](https://pkg.go.dev/regexp/syntax).",
+				Optional:            true,
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "Only return indexes whose name starts with this prefix.",
+				Optional:            true,
+			},
+			"spec_type": schema.StringAttribute{
+				MarkdownDescription: "Only return indexes of this type. One of 'pod' or 'serverless'.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("pod", "serverless"),
+				},
+			},
+			"cloud": schema.StringAttribute{
+				MarkdownDescription: "Only return indexes hosted in this cloud. For pod-based indexes the cloud is taken from the environment, e.g. 'gcp' for 'us-west4-gcp'.",
+				Optional:            true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Only return indexes hosted in this region. For pod-based indexes the region is taken from the environment, e.g. 'us-west4' for 'us-west4-gcp'.",
+				Optional:            true,
+			},
+			"ready_only": schema.BoolAttribute{
+				MarkdownDescription: "Only return indexes that are ready.",
+				Optional:            true,
+			},
 			"id": schema.StringAttribute{
-				MarkdownDescription: "Indexes identifier",
+				MarkdownDescription: "Indexes identifier, derived from the names of the matching indexes.",
 				Computed:            true,
 			},
 		},
 	}
+	addAttributes(resp.Schema.Attributes, credentialDataSourceAttributes())
 }
 
 func (d *IndexesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -159,29 +102,144 @@ func (d *IndexesDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	indexes, err := d.client.ListIndexes(ctx)
+	filter, err := newIndexFilter(&data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid name_regex", err.Error())
+		return
+	}
+
+	api, diags := d.clientFor(ctx, data.ProjectApiKey, data.Profile)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	indexes, err := api.ListIndexes(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to ListIndexes, got error: %s", err))
 		return
 	}
 
+	sort.Slice(indexes, func(i, j int) bool {
+		return indexes[i].Name < indexes[j].Name
+	})
+
+	data.Indexes = []models.IndexModel{}
+	names := []string{}
 	for _, i := range indexes {
+		if !filter.Match(i) {
+			continue
+		}
 		index := models.IndexModel{}
 		resp.Diagnostics.Append(index.Read(ctx, i)...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
 		data.Indexes = append(data.Indexes, index)
+		names = append(names, i.Name)
 	}
 
-	// For the purposes of this Indexes code, hardcoding a response value to
-	// save into the Terraform state.
-	data.Id = types.StringValue(strconv.FormatInt(time.Now().Unix(), 10))
+	data.Names, diags = types.ListValueFrom(ctx, types.StringType, names)
+	resp.Diagnostics.Append(diags...)
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	// tflog.Trace(ctx, "read a data source")
+	// Derive the identifier from the result so it only changes when the matching indexes do.
+	data.Id = types.StringValue(hashStrings(names))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// indexFilter selects indexes by the filter attributes of the pinecone_indexes data source.
+type indexFilter struct {
+	nameRegex  *regexp.Regexp
+	namePrefix string
+	specType   string
+	cloud      string
+	region     string
+	readyOnly  bool
+}
+
+func newIndexFilter(data *models.IndexesDataSourceModel) (*indexFilter, error) {
+	filter := &indexFilter{
+		namePrefix: data.NamePrefix.ValueString(),
+		specType:   data.SpecType.ValueString(),
+		cloud:      data.Cloud.ValueString(),
+		region:     data.Region.ValueString(),
+		readyOnly:  data.ReadyOnly.ValueBool(),
+	}
+	if !data.NameRegex.IsNull() {
+		re, err := regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		filter.nameRegex = re
+	}
+	return filter, nil
+}
+
+// Match reports whether the index passes every configured filter.
+func (f *indexFilter) Match(index *pinecone.Index) bool {
+	if f.nameRegex != nil && !f.nameRegex.MatchString(index.Name) {
+		return false
+	}
+	if f.namePrefix != "" && !strings.HasPrefix(index.Name, f.namePrefix) {
+		return false
+	}
+	if f.readyOnly && (index.Status == nil || !index.Status.Ready) {
+		return false
+	}
+
+	specType, cloud, region := indexLocation(index)
+	if f.specType != "" && f.specType != specType {
+		return false
+	}
+	if f.cloud != "" && f.cloud != cloud {
+		return false
+	}
+	if f.region != "" && f.region != region {
+		return false
+	}
+	return true
+}
+
+// indexLocation returns the type of the index and where it is hosted. Pod-based indexes
+// only report an environment such as "us-west4-gcp", which is split into its region
+// and cloud.
+func indexLocation(index *pinecone.Index) (specType string, cloud string, region string) {
+	if index.Spec == nil {
+		return "", "", ""
+	}
+	if index.Spec.Serverless != nil {
+		return "serverless", string(index.Spec.Serverless.Cloud), index.Spec.Serverless.Region
+	}
+	if index.Spec.Pod != nil {
+		cloud, region := splitEnvironment(index.Spec.Pod.Environment)
+		return "pod", cloud, region
+	}
+	return "", "", ""
+}
+
+// podClouds are the clouds that end the environment of a pod-based index.
+var podClouds = []string{"aws", "gcp", "azure"}
+
+// splitEnvironment splits the environment of a pod-based index, such as "us-west4-gcp"
+// or "us-east-1-aws", into its cloud and region. The environment of the starter plan,
+// "gcp-starter", is hosted in us-central1. An environment that does not end in a known
+// cloud is returned as the region.
+func splitEnvironment(env string) (cloud string, region string) {
+	if env == "gcp-starter" {
+		return "gcp", "us-central1"
+	}
+	for _, cloud := range podClouds {
+		if region, ok := strings.CutSuffix(env, "-"+cloud); ok && region != "" {
+			return cloud, region
+		}
+	}
+	return "", env
+}
+
+// hashStrings returns a stable identifier for a list of strings.
+func hashStrings(values []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(values, "\n")))
+	return hex.EncodeToString(sum[:])
+}
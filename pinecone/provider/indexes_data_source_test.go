@@ -4,11 +4,18 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
 )
 
 func TestAccIndexesDataSource(t *testing.T) {
@@ -29,6 +36,169 @@ func TestAccIndexesDataSource(t *testing.T) {
 	})
 }
 
+func TestAccIndexesDataSource_filtered(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tftest")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccIndexesDataSourceConfig_filtered(rName),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pinecone_indexes.test", "names.#", "1"),
+					resource.TestCheckResourceAttr("data.pinecone_indexes.test", "names.0", rName),
+					resource.TestCheckResourceAttr("data.pinecone_indexes.test", "indexes.0.spec.serverless.region", "us-west-2"),
+				),
+			},
+		},
+	})
+}
+
+func TestIndexFilter_Match(t *testing.T) {
+	serverless := &pinecone.Index{
+		Name:   "prod-search",
+		Spec:   &pinecone.IndexSpec{Serverless: &pinecone.ServerlessSpec{Cloud: pinecone.Aws, Region: "us-west-2"}},
+		Status: &pinecone.IndexStatus{Ready: true, State: pinecone.Ready},
+	}
+	pod := &pinecone.Index{
+		Name:   "staging-search",
+		Spec:   &pinecone.IndexSpec{Pod: &pinecone.PodSpec{Environment: "us-west4-gcp"}},
+		Status: &pinecone.IndexStatus{State: pinecone.Initializing},
+	}
+
+	cases := map[string]struct {
+		data       models.IndexesDataSourceModel
+		serverless bool
+		pod        bool
+	}{
+		"no filters":  {models.IndexesDataSourceModel{}, true, true},
+		"name_regex":  {models.IndexesDataSourceModel{NameRegex: types.StringValue("^prod-")}, true, false},
+		"name_prefix": {models.IndexesDataSourceModel{NamePrefix: types.StringValue("staging")}, false, true},
+		"spec_type":   {models.IndexesDataSourceModel{SpecType: types.StringValue("pod")}, false, true},
+		"cloud":       {models.IndexesDataSourceModel{Cloud: types.StringValue("gcp")}, false, true},
+		"region":      {models.IndexesDataSourceModel{Region: types.StringValue("us-west-2")}, true, false},
+		"pod region":  {models.IndexesDataSourceModel{Region: types.StringValue("us-west4")}, false, true},
+		"ready_only":  {models.IndexesDataSourceModel{ReadyOnly: types.BoolValue(true)}, true, false},
+	}
+
+	for name, c := range cases {
+		filter, err := newIndexFilter(&c.data)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if got := filter.Match(serverless); got != c.serverless {
+			t.Errorf("%s: expected serverless match %t, got %t", name, c.serverless, got)
+		}
+		if got := filter.Match(pod); got != c.pod {
+			t.Errorf("%s: expected pod match %t, got %t", name, c.pod, got)
+		}
+	}
+
+	if _, err := newIndexFilter(&models.IndexesDataSourceModel{NameRegex: types.StringValue("(")}); err == nil {
+		t.Error("Expected an error for an invalid name_regex")
+	}
+}
+
+func TestSplitEnvironment(t *testing.T) {
+	cases := map[string]struct {
+		cloud  string
+		region string
+	}{
+		"us-west4-gcp":  {"gcp", "us-west4"},
+		"us-east-1-aws": {"aws", "us-east-1"},
+		"eastus-azure":  {"azure", "eastus"},
+		"gcp-starter":   {"gcp", "us-central1"},
+		"aws":           {"", "aws"},
+		"-gcp":          {"", "-gcp"},
+		"us-west4":      {"", "us-west4"},
+		"":              {"", ""},
+	}
+
+	for env, c := range cases {
+		cloud, region := splitEnvironment(env)
+		if cloud != c.cloud || region != c.region {
+			t.Errorf("%q: expected (%q, %q), got (%q, %q)", env, c.cloud, c.region, cloud, region)
+		}
+	}
+}
+
+func TestHashStrings(t *testing.T) {
+	if hashStrings([]string{"a", "b"}) != hashStrings([]string{"a", "b"}) {
+		t.Error("Expected the hash to be stable")
+	}
+	if hashStrings([]string{"a", "b"}) == hashStrings([]string{"ab"}) {
+		t.Error("Expected different lists to hash differently")
+	}
+}
+
+func TestIndexesDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	memory := client.NewMemory()
+	for name, index := range testIndexes() {
+		index.Name = name
+		memory.PutIndex(index)
+	}
+	d := NewIndexesDataSource().(*IndexesDataSource)
+	s := configureTestDataSource(t, d, newTestProviderData(memory))
+
+	config := models.IndexesDataSourceModel{
+		Names:      types.ListNull(types.StringType),
+		NameRegex:  types.StringNull(),
+		NamePrefix: types.StringNull(),
+		SpecType:   types.StringValue("serverless"),
+		Cloud:      types.StringNull(),
+		Region:     types.StringNull(),
+		ReadyOnly:  types.BoolNull(),
+		Id:         types.StringNull(),
+	}
+	resp := &datasource.ReadResponse{State: newTestDataSourceState(s)}
+	d.Read(ctx, datasource.ReadRequest{Config: newTestConfig(t, s, &config)}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("read failed: %v", resp.Diagnostics)
+	}
+
+	var state models.IndexesDataSourceModel
+	var names []string
+	resp.Diagnostics.Append(resp.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(state.Names.ElementsAs(ctx, &names, false)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("invalid state: %v", resp.Diagnostics)
+	}
+	if expected := []string{"no status", "serverless"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected names %v, got %v", expected, names)
+	}
+	if len(state.Indexes) != 2 || state.Id.ValueString() != hashStrings(names) {
+		t.Errorf("unexpected state: %+v", state)
+	}
+}
+
+func TestIndexesDataSource_Read_error(t *testing.T) {
+	memory := client.NewMemory()
+	memory.Hook = func(op client.Operation, name string) error {
+		return fmt.Errorf("unexpected status code: 503")
+	}
+	d := NewIndexesDataSource().(*IndexesDataSource)
+	s := configureTestDataSource(t, d, newTestProviderData(memory))
+
+	config := models.IndexesDataSourceModel{
+		Names:      types.ListNull(types.StringType),
+		NameRegex:  types.StringNull(),
+		NamePrefix: types.StringNull(),
+		SpecType:   types.StringNull(),
+		Cloud:      types.StringNull(),
+		Region:     types.StringNull(),
+		ReadyOnly:  types.BoolNull(),
+		Id:         types.StringNull(),
+	}
+	resp := &datasource.ReadResponse{State: newTestDataSourceState(s)}
+	d.Read(context.Background(), datasource.ReadRequest{Config: newTestConfig(t, s, &config)}, resp)
+	if !resp.Diagnostics.HasError() {
+		t.Error("expected a failed list to be reported")
+	}
+}
+
 func testAccIndexesDataSourceConfig(name string) string {
 	return fmt.Sprintf(`
 	provider "pinecone" {
@@ -49,3 +219,28 @@ func testAccIndexesDataSourceConfig(name string) string {
 	}
 	`, name)
 }
+
+func testAccIndexesDataSourceConfig_filtered(name string) string {
+	return fmt.Sprintf(`
+provider "pinecone" {
+}
+
+resource "pinecone_index" "test" {
+  name = %q
+  dimension = 1536
+  spec = {
+	serverless = {
+		cloud = "aws"
+		region = "us-west-2"
+	}
+  }
+}
+
+data "pinecone_indexes" "test" {
+  name_regex = "^${pinecone_index.test.name}$"
+  spec_type  = "serverless"
+  cloud      = "aws"
+  ready_only = true
+}
+`, name)
+}
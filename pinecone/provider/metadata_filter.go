@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+)
+
+// metadataPredicate reports whether the metadata of a vector matches a filter.
+type metadataPredicate func(metadata map[string]interface{}) bool
+
+// parseMetadataFilter compiles a filter written in the metadata filter language of
+// queries, such as {"genre": {"$in": ["comedy", "drama"]}, "year": {"$gte": 2020}}, so
+// that fetched vectors can be filtered by the provider.
+func parseMetadataFilter(filter string) (metadataPredicate, error) {
+	var expr map[string]interface{}
+	if err := json.Unmarshal([]byte(filter), &expr); err != nil {
+		return nil, fmt.Errorf("filter is not a JSON object: %w", err)
+	}
+	return compileFilter(expr)
+}
+
+// matchMetadata reports whether the metadata of vector matches match.
+func matchMetadata(match metadataPredicate, vector *pinecone.Vector) bool {
+	var metadata map[string]interface{}
+	if vector.Metadata != nil {
+		metadata = vector.Metadata.AsMap()
+	}
+	return match(metadata)
+}
+
+func compileFilter(expr map[string]interface{}) (metadataPredicate, error) {
+	var predicates []metadataPredicate
+	for _, key := range sortedKeys(expr) {
+		value := expr[key]
+		switch key {
+		case "$and", "$or":
+			clauses, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s takes a list of filters", key)
+			}
+			var operands []metadataPredicate
+			for _, clause := range clauses {
+				obj, ok := clause.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s takes a list of filters", key)
+				}
+				p, err := compileFilter(obj)
+				if err != nil {
+					return nil, err
+				}
+				operands = append(operands, p)
+			}
+			if key == "$and" {
+				predicates = append(predicates, allOf(operands))
+			} else {
+				predicates = append(predicates, anyOf(operands))
+			}
+		default:
+			if strings.HasPrefix(key, "$") {
+				return nil, fmt.Errorf("unsupported operator %s", key)
+			}
+			p, err := compileField(key, value)
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, p)
+		}
+	}
+	return allOf(predicates), nil
+}
+
+// compileField compiles the conditions on a single field. A bare value is shorthand
+// for $eq.
+func compileField(field string, condition interface{}) (metadataPredicate, error) {
+	ops, ok := condition.(map[string]interface{})
+	if !ok {
+		ops = map[string]interface{}{"$eq": condition}
+	}
+
+	var predicates []metadataPredicate
+	for _, op := range sortedKeys(ops) {
+		op, operand := op, ops[op]
+		var test func(value interface{}, present bool) bool
+
+		switch op {
+		case "$eq", "$ne":
+			if !isScalar(operand) {
+				return nil, fmt.Errorf("%s on %s takes a string, number or boolean", op, field)
+			}
+			want := op == "$eq"
+			test = func(value interface{}, present bool) bool {
+				if !present {
+					return !want
+				}
+				return containsValue(value, []interface{}{operand}) == want
+			}
+		case "$in", "$nin":
+			list, ok := operand.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s on %s takes a list", op, field)
+			}
+			for _, v := range list {
+				if !isScalar(v) {
+					return nil, fmt.Errorf("%s on %s takes a list of strings, numbers or booleans", op, field)
+				}
+			}
+			want := op == "$in"
+			test = func(value interface{}, present bool) bool {
+				if !present {
+					return !want
+				}
+				return containsValue(value, list) == want
+			}
+		case "$gt", "$gte", "$lt", "$lte":
+			bound, ok := operand.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s on %s takes a number", op, field)
+			}
+			test = func(value interface{}, present bool) bool {
+				n, ok := value.(float64)
+				if !present || !ok {
+					return false
+				}
+				switch op {
+				case "$gt":
+					return n > bound
+				case "$gte":
+					return n >= bound
+				case "$lt":
+					return n < bound
+				}
+				return n <= bound
+			}
+		case "$exists":
+			want, ok := operand.(bool)
+			if !ok {
+				return nil, fmt.Errorf("$exists on %s takes a boolean", field)
+			}
+			test = func(value interface{}, present bool) bool {
+				return present == want
+			}
+		default:
+			return nil, fmt.Errorf("unsupported operator %s on %s", op, field)
+		}
+
+		predicates = append(predicates, func(metadata map[string]interface{}) bool {
+			value, present := metadata[field]
+			return test(value, present)
+		})
+	}
+	return allOf(predicates), nil
+}
+
+// containsValue reports whether value, or any element of value when it is a list of
+// strings, equals one of candidates.
+func containsValue(value interface{}, candidates []interface{}) bool {
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+	for _, v := range values {
+		if !isScalar(v) {
+			continue
+		}
+		for _, c := range candidates {
+			if v == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, float64, bool:
+		return true
+	}
+	return false
+}
+
+func allOf(predicates []metadataPredicate) metadataPredicate {
+	return func(metadata map[string]interface{}) bool {
+		for _, p := range predicates {
+			if !p(metadata) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func anyOf(predicates []metadataPredicate) metadataPredicate {
+	return func(metadata map[string]interface{}) bool {
+		for _, p := range predicates {
+			if p(metadata) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestParseMetadataFilter(t *testing.T) {
+	metadata, err := structpb.NewStruct(map[string]interface{}{
+		"genre": "comedy",
+		"year":  2021,
+		"tags":  []interface{}{"classic", "family"},
+		"draft": false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vector := &pinecone.Vector{Id: "a", Metadata: metadata}
+
+	cases := []struct {
+		filter string
+		want   bool
+	}{
+		{`{}`, true},
+		{`{"genre": "comedy"}`, true},
+		{`{"genre": {"$eq": "drama"}}`, false},
+		{`{"genre": {"$ne": "drama"}}`, true},
+		{`{"rating": {"$ne": 5}}`, true},
+		{`{"genre": {"$in": ["drama", "comedy"]}}`, true},
+		{`{"genre": {"$nin": ["drama", "comedy"]}}`, false},
+		{`{"tags": "family"}`, true},
+		{`{"tags": {"$nin": ["horror"]}}`, true},
+		{`{"year": {"$gte": 2021, "$lt": 2022}}`, true},
+		{`{"year": {"$gt": 2021}}`, false},
+		{`{"year": {"$lte": 2020}}`, false},
+		{`{"genre": {"$gt": 1}}`, false},
+		{`{"draft": false}`, true},
+		{`{"rating": {"$exists": false}}`, true},
+		{`{"genre": "comedy", "year": 2020}`, false},
+		{`{"$or": [{"genre": "drama"}, {"year": 2021}]}`, true},
+		{`{"$and": [{"genre": "comedy"}, {"$or": [{"draft": true}, {"tags": "horror"}]}]}`, false},
+	}
+	for _, c := range cases {
+		match, err := parseMetadataFilter(c.filter)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.filter, err)
+			continue
+		}
+		if got := matchMetadata(match, vector); got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.filter, c.want, got)
+		}
+	}
+
+	if match, _ := parseMetadataFilter(`{"genre": {"$exists": true}}`); matchMetadata(match, &pinecone.Vector{Id: "b"}) {
+		t.Error("expected a vector without metadata not to match $exists")
+	}
+}
+
+func TestParseMetadataFilter_invalid(t *testing.T) {
+	for _, filter := range []string{
+		`["genre"]`,
+		`{"genre": {"$regex": "com.*"}}`,
+		`{"$not": {"genre": "comedy"}}`,
+		`{"genre": {"$in": "comedy"}}`,
+		`{"genre": {"$eq": ["comedy"]}}`,
+		`{"year": {"$gt": "2020"}}`,
+		`{"$or": {"genre": "comedy"}}`,
+		`{"genre": {"$exists": 1}}`,
+	} {
+		if _, err := parseMetadataFilter(filter); err == nil {
+			t.Errorf("%s: expected an error", filter)
+		}
+	}
+}
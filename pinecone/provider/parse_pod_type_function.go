@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// podFamilies and podSizes hold the parts of the valid pod types, such as "s1.x1".
+var (
+	podFamilies = []string{"s1", "p1", "p2"}
+	podSizes    = []string{"x1", "x2", "x4", "x8"}
+)
+
+// parsePodType splits a pod type into its family and size.
+func parsePodType(podType string) (family string, size string, err error) {
+	family, size, ok := strings.Cut(podType, ".")
+	if !ok || !slices.Contains(podFamilies, family) || !slices.Contains(podSizes, size) {
+		return "", "", fmt.Errorf("%q is not a pod type. Pod types are one of %s, followed by . and one of %s",
+			podType, strings.Join(podFamilies, ", "), strings.Join(podSizes, ", "))
+	}
+	return family, size, nil
+}
+
+var _ function.Function = &ParsePodTypeFunction{}
+
+func NewParsePodTypeFunction() function.Function {
+	return &ParsePodTypeFunction{}
+}
+
+// ParsePodTypeFunction splits a pod type into its family and size.
+type ParsePodTypeFunction struct{}
+
+var parsePodTypeAttrTypes = map[string]attr.Type{
+	"family": types.StringType,
+	"size":   types.StringType,
+}
+
+func (f *ParsePodTypeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_pod_type"
+}
+
+func (f *ParsePodTypeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Split a pod type into its family and size",
+		MarkdownDescription: "Splits a pod type such as `s1.x2` into an object with its `family`, `s1`, and its `size`, `x2`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "pod_type",
+				MarkdownDescription: "The pod type. One of s1, p1, or p2 appended with . and one of x1, x2, x4, or x8.",
+			},
+		},
+		Return: function.ObjectReturn{AttributeTypes: parsePodTypeAttrTypes},
+	}
+}
+
+func (f *ParsePodTypeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var podType string
+	resp.Error = req.Arguments.Get(ctx, &podType)
+	if resp.Error != nil {
+		return
+	}
+
+	family, size, err := parsePodType(podType)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, types.ObjectValueMust(parsePodTypeAttrTypes, map[string]attr.Value{
+		"family": types.StringValue(family),
+		"size":   types.StringValue(size),
+	}))
+}
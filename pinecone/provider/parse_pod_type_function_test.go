@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestParsePodTypeFunction(t *testing.T) {
+	result, err := runTestFunction(t, NewParsePodTypeFunction(), types.StringValue("p2.x4"))
+	want := types.ObjectValueMust(parsePodTypeAttrTypes, map[string]attr.Value{
+		"family": types.StringValue("p2"),
+		"size":   types.StringValue("x4"),
+	})
+	if err != nil || !result.Equal(want) {
+		t.Errorf("expected %v, got %v: %v", want, result, err)
+	}
+
+	for _, podType := range []string{"s1", "s1.x3", "s2.x1", "s1.x1.x1", ""} {
+		if _, err := runTestFunction(t, NewParsePodTypeFunction(), types.StringValue(podType)); err == nil {
+			t.Errorf("%q: expected an error", podType)
+		}
+	}
+}
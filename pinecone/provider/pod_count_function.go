@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &PodCountFunction{}
+
+func NewPodCountFunction() function.Function {
+	return &PodCountFunction{}
+}
+
+// PodCountFunction returns the number of pods used by a pod index.
+type PodCountFunction struct{}
+
+func (f *PodCountFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "pod_count"
+}
+
+func (f *PodCountFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Number of pods used by a pod index",
+		MarkdownDescription: "Returns the number of pods used by a pod index with the given shards and replicas, the same value as `spec.pod.pods` of `pinecone_index`.",
+		Parameters: []function.Parameter{
+			function.Int64Parameter{
+				Name:                "shards",
+				MarkdownDescription: "The number of shards, at least 1.",
+			},
+			function.Int64Parameter{
+				Name:                "replicas",
+				MarkdownDescription: "The number of replicas, at least 1.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *PodCountFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var shards, replicas int64
+	resp.Error = req.Arguments.Get(ctx, &shards, &replicas)
+	if resp.Error != nil {
+		return
+	}
+
+	if shards < 1 {
+		resp.Error = function.NewArgumentFuncError(0, "shards must be at least 1")
+		return
+	}
+	if replicas < 1 {
+		resp.Error = function.NewArgumentFuncError(1, "replicas must be at least 1")
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, shards*replicas)
+}
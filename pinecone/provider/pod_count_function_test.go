@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestPodCountFunction(t *testing.T) {
+	result, err := runTestFunction(t, NewPodCountFunction(), types.Int64Value(2), types.Int64Value(3))
+	if err != nil || !result.Equal(types.Int64Value(6)) {
+		t.Errorf("expected 6 pods, got %v: %v", result, err)
+	}
+
+	if _, err := runTestFunction(t, NewPodCountFunction(), types.Int64Value(0), types.Int64Value(3)); err == nil || *err.FunctionArgument != 0 {
+		t.Errorf("expected an error on shards, got %v", err)
+	}
+	if _, err := runTestFunction(t, NewPodCountFunction(), types.Int64Value(1), types.Int64Value(-1)); err == nil || *err.FunctionArgument != 1 {
+		t.Errorf("expected an error on replicas, got %v", err)
+	}
+}
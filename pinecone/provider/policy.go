@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+// IndexPolicy restricts the indexes that may be planned. An empty list or a zero limit
+// allows anything.
+type IndexPolicy struct {
+	AllowedClouds  []string
+	AllowedRegions []string
+	// AllowedPodTypes holds pod types such as "s1.x1", or families such as "s1.*".
+	AllowedPodTypes []string
+	MaxPods         int64
+	NameRegex       *regexp.Regexp
+}
+
+// newIndexPolicy reads the policy block of the provider configuration. It returns nil
+// when the block is not set.
+func newIndexPolicy(ctx context.Context, obj types.Object) (*IndexPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, diags
+	}
+
+	var model PineconePolicyModel
+	diags.Append(obj.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	policy := &IndexPolicy{}
+	for _, list := range []struct {
+		value  types.List
+		target *[]string
+	}{
+		{model.AllowedClouds, &policy.AllowedClouds},
+		{model.AllowedRegions, &policy.AllowedRegions},
+		{model.AllowedPodTypes, &policy.AllowedPodTypes},
+	} {
+		if !list.value.IsNull() && !list.value.IsUnknown() {
+			diags.Append(list.value.ElementsAs(ctx, list.target, false)...)
+		}
+	}
+	if !model.MaxPods.IsNull() && !model.MaxPods.IsUnknown() {
+		policy.MaxPods = model.MaxPods.ValueInt64()
+	}
+	if !model.NameRegex.IsNull() && !model.NameRegex.IsUnknown() {
+		re, err := regexp.Compile(model.NameRegex.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("policy").AtName("name_regex"), "Invalid name_regex", err.Error())
+		}
+		policy.NameRegex = re
+	}
+	return policy, diags
+}
+
+// Check reports every way in which a planned index violates the policy. Attributes that
+// are not known yet are not checked.
+func (p *IndexPolicy) Check(ctx context.Context, data *models.IndexResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	diags.Append(p.checkName(path.Root("name"), data.Name)...)
+
+	if data.Spec.IsNull() || data.Spec.IsUnknown() {
+		return diags
+	}
+	var spec models.IndexSpecModel
+	if d := data.Spec.As(ctx, &spec, basetypes.ObjectAsOptions{}); d.HasError() {
+		diags.Append(d...)
+		return diags
+	}
+
+	if spec.Serverless != nil {
+		serverless := path.Root("spec").AtName("serverless")
+		if isKnown(spec.Serverless.Cloud) {
+			diags.Append(p.checkAllowed(serverless.AtName("cloud"), "Cloud", spec.Serverless.Cloud.ValueString(), p.AllowedClouds)...)
+		}
+		if isKnown(spec.Serverless.Region) {
+			diags.Append(p.checkAllowed(serverless.AtName("region"), "Region", spec.Serverless.Region.ValueString(), p.AllowedRegions)...)
+		}
+	}
+
+	if spec.Pod != nil {
+		pod := path.Root("spec").AtName("pod")
+		if isKnown(spec.Pod.Environment) {
+			cloud, region := splitEnvironment(spec.Pod.Environment.ValueString())
+			diags.Append(p.checkAllowed(pod.AtName("environment"), "Cloud", cloud, p.AllowedClouds)...)
+			diags.Append(p.checkAllowed(pod.AtName("environment"), "Region", region, p.AllowedRegions)...)
+		}
+		if isKnown(spec.Pod.PodType) && !podTypeAllowed(spec.Pod.PodType.ValueString(), p.AllowedPodTypes) {
+			diags.AddAttributeError(pod.AtName("pod_type"), "Pod type not allowed by policy",
+				fmt.Sprintf("Pod type %q is not allowed by the provider policy. Allowed pod types: %s.", spec.Pod.PodType.ValueString(), strings.Join(p.AllowedPodTypes, ", ")))
+		}
+		if p.MaxPods > 0 && isKnown(spec.Pod.Replicas) && isKnown(spec.Pod.ShardCount) {
+			if pods := spec.Pod.Replicas.ValueInt64() * spec.Pod.ShardCount.ValueInt64(); pods > p.MaxPods {
+				diags.AddAttributeError(pod, "Too many pods for policy",
+					fmt.Sprintf("The index would use %d pods (%d replicas × %d shards), more than the %d allowed by the provider policy.",
+						pods, spec.Pod.Replicas.ValueInt64(), spec.Pod.ShardCount.ValueInt64(), p.MaxPods))
+			}
+		}
+	}
+	return diags
+}
+
+// CheckChange is Check for a plan that changes prior, the index in state, which is nil
+// when the index is created. Violations the index already had are not reported, so that
+// tightening the policy does not block plans that leave those attributes alone.
+func (p *IndexPolicy) CheckChange(ctx context.Context, data *models.IndexResourceModel, prior *models.IndexResourceModel) diag.Diagnostics {
+	if prior == nil {
+		return p.Check(ctx, data)
+	}
+	return newViolations(p.Check(ctx, data), p.Check(ctx, prior))
+}
+
+// CheckMigration reports every way in which the serverless index an index migration
+// creates violates the policy. Like CheckChange, it does not report the violations of
+// prior, the migration in state, which is nil when the migration is created.
+func (p *IndexPolicy) CheckMigration(data *models.IndexMigrationResourceModel, prior *models.IndexMigrationResourceModel) diag.Diagnostics {
+	check := func(data *models.IndexMigrationResourceModel) diag.Diagnostics {
+		var diags diag.Diagnostics
+		diags.Append(p.checkName(path.Root("target_name"), data.TargetName)...)
+		if isKnown(data.TargetCloud) {
+			diags.Append(p.checkAllowed(path.Root("target_cloud"), "Cloud", data.TargetCloud.ValueString(), p.AllowedClouds)...)
+		}
+		if isKnown(data.TargetRegion) {
+			diags.Append(p.checkAllowed(path.Root("target_region"), "Region", data.TargetRegion.ValueString(), p.AllowedRegions)...)
+		}
+		return diags
+	}
+	if prior == nil {
+		return check(data)
+	}
+	return newViolations(check(data), check(prior))
+}
+
+// newViolations returns the diagnostics of diags that are not in existing.
+func newViolations(diags diag.Diagnostics, existing diag.Diagnostics) diag.Diagnostics {
+	var changed diag.Diagnostics
+	for _, d := range diags {
+		if !existing.Contains(d) {
+			changed.Append(d)
+		}
+	}
+	return changed
+}
+
+func (p *IndexPolicy) checkName(attr path.Path, name types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if p.NameRegex != nil && isKnown(name) && !p.NameRegex.MatchString(name.ValueString()) {
+		diags.AddAttributeError(attr, "Index name not allowed by policy",
+			fmt.Sprintf("Index name %q does not match the pattern %s required by the provider policy.", name.ValueString(), p.NameRegex))
+	}
+	return diags
+}
+
+func (p *IndexPolicy) checkAllowed(attr path.Path, kind string, value string, allowed []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(allowed) == 0 {
+		return diags
+	}
+	for _, a := range allowed {
+		if value == a {
+			return diags
+		}
+	}
+	diags.AddAttributeError(attr, fmt.Sprintf("%s not allowed by policy", kind),
+		fmt.Sprintf("%s %q is not allowed by the provider policy. Allowed: %s.", kind, value, strings.Join(allowed, ", ")))
+	return diags
+}
+
+// podTypeAllowed reports whether podType is one of allowed, where a pattern such as
+// "s1.*" allows every size of a family.
+func podTypeAllowed(podType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == podType || (strings.HasSuffix(a, ".*") && strings.HasPrefix(podType, strings.TrimSuffix(a, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+type knownValue interface {
+	IsNull() bool
+	IsUnknown() bool
+}
+
+// isKnown reports whether v holds a value that can be checked.
+func isKnown(v knownValue) bool {
+	return !v.IsNull() && !v.IsUnknown()
+}
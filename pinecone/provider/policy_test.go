@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+func testPolicy() *IndexPolicy {
+	return &IndexPolicy{
+		AllowedClouds:   []string{"aws", "gcp"},
+		AllowedRegions:  []string{"us-west-2", "us-west4"},
+		AllowedPodTypes: []string{"s1.*", "p1.x1"},
+		MaxPods:         4,
+		NameRegex:       regexp.MustCompile(`^prod-`),
+	}
+}
+
+func testPolicyPodSpec(environment, podType string, replicas, shards types.Int64) *models.IndexPodSpecModel {
+	return &models.IndexPodSpecModel{
+		Environment:      types.StringValue(environment),
+		PodType:          types.StringValue(podType),
+		Replicas:         replicas,
+		ShardCount:       shards,
+		PodCount:         types.Int64Unknown(),
+		MetadataConfig:   types.ObjectUnknown(models.IndexMetadataConfigModel{}.AttrTypes()),
+		SourceCollection: types.StringNull(),
+	}
+}
+
+// testPolicyIndex returns a planned index with the attributes a policy checks.
+func testPolicyIndex(t *testing.T, name types.String, spec models.IndexSpecModel) models.IndexResourceModel {
+	t.Helper()
+
+	specValue, diags := types.ObjectValueFrom(context.Background(), models.IndexSpecModel{}.AttrTypes(), spec)
+	if diags.HasError() {
+		t.Fatalf("invalid spec: %v", diags)
+	}
+	return models.IndexResourceModel{Name: name, Spec: specValue}
+}
+
+// attributePaths returns the attribute path of every error in diags.
+func attributePaths(diags diag.Diagnostics) []string {
+	var paths []string
+	for _, d := range diags.Errors() {
+		if d, ok := d.(diag.DiagnosticWithPath); ok {
+			paths = append(paths, d.Path().String())
+		}
+	}
+	return paths
+}
+
+func TestIndexPolicy_Check(t *testing.T) {
+	ctx := context.Background()
+	serverless := func(cloud, region string) models.IndexSpecModel {
+		return models.IndexSpecModel{Serverless: &models.IndexServerlessSpecModel{
+			Cloud:  types.StringValue(cloud),
+			Region: types.StringValue(region),
+		}}
+	}
+	pod := func(environment, podType string, replicas, shards int64) models.IndexSpecModel {
+		return models.IndexSpecModel{Pod: testPolicyPodSpec(environment, podType, types.Int64Value(replicas), types.Int64Value(shards))}
+	}
+
+	cases := map[string]struct {
+		name string
+		spec models.IndexSpecModel
+		want []string
+	}{
+		"serverless allowed": {"prod-a", serverless("aws", "us-west-2"), nil},
+		"pod allowed":        {"prod-a", pod("us-west4-gcp", "s1.x4", 2, 2), nil},
+		"exact pod type":     {"prod-a", pod("us-west4-gcp", "p1.x1", 1, 1), nil},
+		"name":               {"dev-a", serverless("aws", "us-west-2"), []string{"name"}},
+		"serverless cloud":   {"prod-a", serverless("azure", "us-west-2"), []string{"spec.serverless.cloud"}},
+		"serverless region":  {"prod-a", serverless("aws", "eu-west-1"), []string{"spec.serverless.region"}},
+		"pod environment":    {"prod-a", pod("eu-west1-azure", "s1.x1", 1, 1), []string{"spec.pod.environment", "spec.pod.environment"}},
+		"pod hyphenated":     {"prod-a", pod("us-west-2-aws", "s1.x1", 1, 1), nil},
+		"pod starter":        {"prod-a", pod("gcp-starter", "s1.x1", 1, 1), []string{"spec.pod.environment"}},
+		"pod type":           {"prod-a", pod("us-west4-gcp", "p2.x1", 1, 1), []string{"spec.pod.pod_type"}},
+		"pod type family":    {"prod-a", pod("us-west4-gcp", "s10.x1", 1, 1), []string{"spec.pod.pod_type"}},
+		"too many pods":      {"prod-a", pod("us-west4-gcp", "s1.x1", 3, 2), []string{"spec.pod"}},
+		"everything":         {"a", pod("eu-west1-azure", "p2.x1", 5, 1), []string{"name", "spec.pod.environment", "spec.pod.environment", "spec.pod.pod_type", "spec.pod"}},
+	}
+
+	for name, c := range cases {
+		data := testPolicyIndex(t, types.StringValue(c.name), c.spec)
+		got := attributePaths(testPolicy().Check(ctx, &data))
+		if len(got) != len(c.want) {
+			t.Errorf("%s: expected errors at %v, got %v", name, c.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: expected errors at %v, got %v", name, c.want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestIndexPolicy_Check_unknown(t *testing.T) {
+	pod := testPolicyPodSpec("", "", types.Int64Unknown(), types.Int64Value(8))
+	pod.Environment = types.StringUnknown()
+	pod.PodType = types.StringUnknown()
+	data := testPolicyIndex(t, types.StringUnknown(), models.IndexSpecModel{Pod: pod})
+
+	if diags := testPolicy().Check(context.Background(), &data); diags.HasError() {
+		t.Errorf("expected unknown values not to be checked, got: %v", diags)
+	}
+}
+
+func TestIndexPolicy_CheckChange(t *testing.T) {
+	ctx := context.Background()
+	pod := func(podType string, replicas int64) models.IndexResourceModel {
+		spec := models.IndexSpecModel{Pod: testPolicyPodSpec("us-west4-gcp", podType, types.Int64Value(replicas), types.Int64Value(1))}
+		return testPolicyIndex(t, types.StringValue("prod-a"), spec)
+	}
+	// The index was created before p2 pods were disallowed.
+	prior := pod("p2.x1", 1)
+
+	cases := map[string]struct {
+		data  models.IndexResourceModel
+		prior *models.IndexResourceModel
+		want  []string
+	}{
+		"create":              {pod("p2.x1", 1), nil, []string{"spec.pod.pod_type"}},
+		"unchanged":           {pod("p2.x1", 1), &prior, nil},
+		"other attribute":     {pod("p2.x1", 2), &prior, nil},
+		"violating attribute": {pod("p2.x2", 1), &prior, []string{"spec.pod.pod_type"}},
+		"new violation":       {pod("p2.x1", 5), &prior, []string{"spec.pod"}},
+		"violation fixed":     {pod("s1.x1", 1), &prior, nil},
+	}
+	for name, c := range cases {
+		got := attributePaths(testPolicy().CheckChange(ctx, &c.data, c.prior))
+		if len(got) != len(c.want) || (len(got) > 0 && got[0] != c.want[0]) {
+			t.Errorf("%s: expected errors at %v, got %v", name, c.want, got)
+		}
+	}
+}
+
+func TestNewIndexPolicy(t *testing.T) {
+	ctx := context.Background()
+	attrTypes := map[string]attr.Type{
+		"allowed_clouds":    types.ListType{ElemType: types.StringType},
+		"allowed_regions":   types.ListType{ElemType: types.StringType},
+		"allowed_pod_types": types.ListType{ElemType: types.StringType},
+		"max_pods":          types.Int64Type,
+		"name_regex":        types.StringType,
+	}
+	value := func(nameRegex string) types.Object {
+		return types.ObjectValueMust(attrTypes, map[string]attr.Value{
+			"allowed_clouds":    types.ListValueMust(types.StringType, []attr.Value{types.StringValue("aws")}),
+			"allowed_regions":   types.ListNull(types.StringType),
+			"allowed_pod_types": types.ListNull(types.StringType),
+			"max_pods":          types.Int64Value(2),
+			"name_regex":        types.StringValue(nameRegex),
+		})
+	}
+
+	if policy, diags := newIndexPolicy(ctx, types.ObjectNull(attrTypes)); policy != nil || diags.HasError() {
+		t.Errorf("expected no policy without a block, got %+v, %v", policy, diags)
+	}
+
+	policy, diags := newIndexPolicy(ctx, value("^prod-"))
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(policy.AllowedClouds) != 1 || policy.AllowedRegions != nil || policy.MaxPods != 2 || policy.NameRegex.String() != "^prod-" {
+		t.Errorf("unexpected policy: %+v", policy)
+	}
+
+	_, diags = newIndexPolicy(ctx, value("(prod"))
+	if got := attributePaths(diags); len(got) != 1 || got[0] != path.Root("policy").AtName("name_regex").String() {
+		t.Errorf("expected an error at policy.name_regex, got: %v", diags)
+	}
+}
@@ -5,18 +5,38 @@ package provider
 
 import (
 	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
 )
 
 // Ensure PineconeProvider satisfies various provider interfaces.
 var _ provider.Provider = &PineconeProvider{}
+var _ provider.ProviderWithConfigValidators = &PineconeProvider{}
+var _ provider.ProviderWithFunctions = &PineconeProvider{}
 
 // PineconeProvider defines the provider implementation.
 type PineconeProvider struct {
@@ -28,7 +48,57 @@ type PineconeProvider struct {
 
 // PineconeProviderModel describes the provider data model.
 type PineconeProviderModel struct {
-	ApiKey types.String `tfsdk:"api_key"`
+	ApiKey                types.String  `tfsdk:"api_key"`
+	ApiKeyFile            types.String  `tfsdk:"api_key_file"`
+	ApiKeyCommand         types.List    `tfsdk:"api_key_command"`
+	Profile               types.String  `tfsdk:"profile"`
+	MaxRetries            types.Int64   `tfsdk:"max_retries"`
+	RetryMinBackoff       types.String  `tfsdk:"retry_min_backoff"`
+	RetryMaxBackoff       types.String  `tfsdk:"retry_max_backoff"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+	RequestsPerSecond     types.Float64 `tfsdk:"requests_per_second"`
+	ProxyUrl              types.String  `tfsdk:"proxy_url"`
+	CaBundleFile          types.String  `tfsdk:"ca_bundle_file"`
+	Headers               types.Map     `tfsdk:"headers"`
+	AppendUserAgent       types.String  `tfsdk:"append_user_agent"`
+	ValidateCredentials   types.Bool    `tfsdk:"validate_credentials"`
+	ExpectedProjectId     types.String  `tfsdk:"expected_project_id"`
+	ReadOnly              types.Bool    `tfsdk:"read_only"`
+	Waiter                types.Object  `tfsdk:"waiter"`
+	DefaultTimeouts       types.Object  `tfsdk:"default_timeouts"`
+	Policy                types.Object  `tfsdk:"policy"`
+	CostEstimate          types.Object  `tfsdk:"cost_estimate"`
+}
+
+// PineconeWaiterModel describes how resources poll for state changes.
+type PineconeWaiterModel struct {
+	PollInterval types.String  `tfsdk:"poll_interval"`
+	MinBackoff   types.String  `tfsdk:"min_backoff"`
+	MaxBackoff   types.String  `tfsdk:"max_backoff"`
+	Jitter       types.Float64 `tfsdk:"jitter"`
+}
+
+// PineconeTimeoutsModel describes the provider-level overrides for resource timeouts.
+type PineconeTimeoutsModel struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
+}
+
+// PineconeCostEstimateModel describes how the costs of indexes are estimated.
+type PineconeCostEstimateModel struct {
+	Prices                   types.Map     `tfsdk:"prices"`
+	MonthlyIncreaseThreshold types.Float64 `tfsdk:"monthly_increase_threshold"`
+}
+
+// PineconePolicyModel describes the restrictions every index must satisfy.
+type PineconePolicyModel struct {
+	AllowedClouds   types.List   `tfsdk:"allowed_clouds"`
+	AllowedRegions  types.List   `tfsdk:"allowed_regions"`
+	AllowedPodTypes types.List   `tfsdk:"allowed_pod_types"`
+	MaxPods         types.Int64  `tfsdk:"max_pods"`
+	NameRegex       types.String `tfsdk:"name_regex"`
 }
 
 func (p *PineconeProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -44,10 +114,209 @@ func (p *PineconeProvider) Schema(ctx context.Context, req provider.SchemaReques
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"api_key_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the Pinecone API Key. Can be configured by setting PINECONE_API_KEY_FILE environment variable.",
+				Optional:            true,
+			},
+			"api_key_command": schema.ListAttribute{
+				MarkdownDescription: "Command, and its arguments, that prints the Pinecone API Key, such as a secrets manager CLI. " +
+					"It is run without a shell every time the provider is configured.",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Name of a profile in `~/.pinecone/config` that sets `api_key`, `api_key_file` or `api_key_command`. " +
+					"Can be configured by setting PINECONE_PROFILE environment variable, and the file can be moved with PINECONE_CONFIG_FILE. " +
+					"When no credentials are configured at all, the `default` profile is used if the file defines it.",
+				Optional: true,
+			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: fmt.Sprintf("Maximum number of times a request to the Pinecone API is retried after a rate limit (429) or server error (5xx). "+
+					"Requests that create indexes or collections are only retried after a rate limit, or when they could not be sent at all, so that nothing is created twice. "+
+					"Set to 0 to disable retries. Defaults to %d.", client.DefaultMaxRetries),
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_min_backoff": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Delay before the first retry, doubling with every further retry. A `Retry-After` header sent by the API takes precedence, up to `retry_max_backoff`. Defaults to %s.", client.DefaultRetryMinBackoff),
+				Optional:            true,
+			},
+			"retry_max_backoff": schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Maximum delay between retries, also when the API asks for a longer one with a `Retry-After` header. Defaults to %s.", client.DefaultRetryMaxBackoff),
+				Optional:            true,
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of HTTP requests to the Pinecone control plane in flight at the same time, across all resources and data sources of this provider. " +
+					"Each request counts, including every retry and every poll while waiting for an index or collection, but waiting between requests does not. " +
+					"Useful to stay within the API rate limits when Terraform manages many indexes in parallel. Unlimited by default.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"requests_per_second": schema.Float64Attribute{
+				MarkdownDescription: "Maximum rate at which requests are sent to the Pinecone control plane, across all resources and data sources of this provider. " +
+					"Requests are spaced evenly rather than sent in bursts. Unlimited by default.",
+				Optional: true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy through which requests to the Pinecone API are sent, such as `http://proxy.example.com:3128`. " +
+					"Can be configured by setting PINECONE_PROXY_URL environment variable. Defaults to the proxy set by the HTTPS_PROXY and NO_PROXY environment variables, " +
+					"which are also the only proxy settings honoured by connections to the data plane of an index.",
+				Optional: true,
+			},
+			"ca_bundle_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM file of certificate authorities trusted in addition to the system ones when connecting to the Pinecone API. " +
+					"Can be configured by setting PINECONE_CA_BUNDLE_FILE environment variable.",
+				Optional: true,
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "Additional HTTP headers sent with every request to the Pinecone API. " +
+					"Can be configured by setting PINECONE_HEADERS environment variable to a comma-separated list of `name=value` pairs; headers set here take precedence.",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"append_user_agent": schema.StringAttribute{
+				MarkdownDescription: "Appended to the user agent, which identifies the provider and Terraform versions. " +
+					"Can be configured by setting TF_APPEND_USER_AGENT environment variable.",
+				Optional: true,
+			},
+			"validate_credentials": schema.BoolAttribute{
+				MarkdownDescription: "Whether to check the API key with a cheap authenticated request when the provider is configured, " +
+					"so that an invalid or revoked key fails early with a clear error. Defaults to false.",
+				Optional: true,
+			},
+			"expected_project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project the API key must belong to. Setting it implies `validate_credentials`. " +
+					"The project is identified by the host of one of its indexes, so it cannot be verified for a project without indexes.",
+				Optional: true,
+			},
+			"read_only": schema.BoolAttribute{
+				MarkdownDescription: "Whether to refuse every change to indexes and collections, failing the plan before any request is sent. " +
+					"Meant for drift detection with credentials that could otherwise make changes. " +
+					"Can be configured by setting PINECONE_READ_ONLY environment variable. Defaults to false.",
+				Optional: true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"waiter": schema.SingleNestedBlock{
+				MarkdownDescription: "Controls how resources poll Pinecone while waiting for indexes and collections to change state. " +
+					"Polling starts at `poll_interval` and backs off exponentially between `min_backoff` and `max_backoff` while the state is unchanged.",
+				Attributes: map[string]schema.Attribute{
+					"poll_interval": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Delay after the first poll, which happens right away, and after every state change. Defaults to %s.", waiter.DefaultPollInterval),
+						Optional:            true,
+					},
+					"min_backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Minimum delay between polls. Defaults to %s.", waiter.DefaultMinBackoff),
+						Optional:            true,
+					},
+					"max_backoff": schema.StringAttribute{
+						MarkdownDescription: fmt.Sprintf("Maximum delay between polls. Defaults to %s.", waiter.DefaultMaxBackoff),
+						Optional:            true,
+					},
+					"jitter": schema.Float64Attribute{
+						MarkdownDescription: fmt.Sprintf("Fraction between 0 and 1 by which every delay is randomised. Defaults to %v.", waiter.DefaultJitter),
+						Optional:            true,
+					},
+				},
+			},
+			"default_timeouts": schema.SingleNestedBlock{
+				MarkdownDescription: "Overrides the built-in timeouts of every resource. A `timeouts` block on a resource still takes precedence. " +
+					"Each value accepts a string that can be [parsed as a duration](https://pkg.go.dev/time#ParseDuration), such as \"30s\" or \"2h45m\".",
+				Attributes: map[string]schema.Attribute{
+					"create": schema.StringAttribute{
+						MarkdownDescription: "Default timeout for create operations.",
+						Optional:            true,
+					},
+					"read": schema.StringAttribute{
+						MarkdownDescription: "Default timeout for read operations.",
+						Optional:            true,
+					},
+					"update": schema.StringAttribute{
+						MarkdownDescription: "Default timeout for update operations.",
+						Optional:            true,
+					},
+					"delete": schema.StringAttribute{
+						MarkdownDescription: "Default timeout for delete operations.",
+						Optional:            true,
+					},
+				},
+			},
+			"cost_estimate": schema.SingleNestedBlock{
+				MarkdownDescription: "Configures the `estimated_hourly_cost` and `estimated_monthly_cost` of pod indexes.",
+				Attributes: map[string]schema.Attribute{
+					"prices": schema.MapAttribute{
+						MarkdownDescription: "Price in USD of one pod for an hour, by pod type, such as `{ \"p1.x1\" = 0.08 }`. " +
+							"Overrides the list prices built into the provider for the same pod types, for example with negotiated rates.",
+						Optional:    true,
+						ElementType: types.Float64Type,
+					},
+					"monthly_increase_threshold": schema.Float64Attribute{
+						MarkdownDescription: "A plan warns when it raises the estimated monthly cost of an index by more than this many USD. " +
+							"Defaults to 0, so every increase warns.",
+						Optional: true,
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0),
+						},
+					},
+				},
+			},
+			"policy": schema.SingleNestedBlock{
+				MarkdownDescription: "Restrictions every `pinecone_index`, and the serverless index of every `pinecone_index_migration`, must satisfy. " +
+					"A plan that violates them fails before anything is changed. " +
+					"They are checked when an index is created and for the attributes a plan changes, so an existing index that violates a new " +
+					"restriction can still be planned as long as the plan leaves those attributes alone. Unset restrictions allow anything. " +
+					"Required tags are not supported, as indexes have no tags in the version of the Pinecone API the provider uses.",
+				Attributes: map[string]schema.Attribute{
+					"allowed_clouds": schema.ListAttribute{
+						MarkdownDescription: "Clouds indexes may be created in, such as \"aws\". The cloud of a pod index is taken from its environment.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+					"allowed_regions": schema.ListAttribute{
+						MarkdownDescription: "Regions indexes may be created in, such as \"us-east-1\". The region of a pod index is taken from its environment.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+					"allowed_pod_types": schema.ListAttribute{
+						MarkdownDescription: "Pod types pod indexes may use, such as \"p1.x1\". A family followed by `.*`, such as \"s1.*\", allows every size.",
+						Optional:            true,
+						ElementType:         types.StringType,
+					},
+					"max_pods": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of pods, replicas times shards, a pod index may use.",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"name_regex": schema.StringAttribute{
+						MarkdownDescription: "Regular expression index names must match, such as \"^prod-[a-z0-9-]+$\".",
+						Optional:            true,
+					},
+				},
+			},
 		},
 	}
 }
 
+func (p *PineconeProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(
+			path.MatchRoot("api_key"),
+			path.MatchRoot("api_key_file"),
+			path.MatchRoot("api_key_command"),
+			path.MatchRoot("profile"),
+		),
+	}
+}
+
 func (p *PineconeProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var data PineconeProviderModel
 
@@ -57,35 +326,350 @@ func (p *PineconeProvider) Configure(ctx context.Context, req provider.Configure
 		return
 	}
 
-	// Default to environment variables, but override
-	// with Terraform configuration value if set.
-	apiKey := os.Getenv("PINECONE_API_KEY")
-	if !data.ApiKey.IsNull() {
-		apiKey = data.ApiKey.ValueString()
+	creds, diags := newCredentials(ctx, &data)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	apiKey, err := creds.resolve(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read Pinecone API key", err.Error())
+		return
 	}
 
-	client, err := pinecone.NewClient(pinecone.NewClientParams{
-		ApiKey:    apiKey,
-		SourceTag: "terraform",
+	retryPolicy, diags := newRetryPolicy(&data)
+	resp.Diagnostics.Append(diags...)
+	limiter, diags := newLimiter(&data)
+	resp.Diagnostics.Append(diags...)
+	options := client.Options{Retry: retryPolicy, Limiter: limiter}
+	resp.Diagnostics.Append(configureHTTP(ctx, &data, &options)...)
+	options.UserAgent = userAgent(p.version, req.TerraformVersion, stringFromEnv(data.AppendUserAgent, "TF_APPEND_USER_AGENT"))
+	waiterConfig, diags := newWaiterConfig(ctx, data.Waiter)
+	resp.Diagnostics.Append(diags...)
+	defaultTimeouts, diags := newDefaultTimeouts(ctx, data.DefaultTimeouts)
+	resp.Diagnostics.Append(diags...)
+	policy, diags := newIndexPolicy(ctx, data.Policy)
+	resp.Diagnostics.Append(diags...)
+	costs, diags := newCostEstimator(ctx, data.CostEstimate)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every client shares the options, and with them the limiter, whichever
+	// project its resources and data sources belong to.
+	clients := NewClientCache(func(apiKey string) (client.ControlPlane, error) {
+		pineconeClient, err := pinecone.NewClient(pinecone.NewClientParams{
+			ApiKey:    apiKey,
+			SourceTag: "terraform",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return client.New(pineconeClient, apiKey, options), nil
 	})
+	defaultClient, err := clients.get(ctx, credentials{apiKey: apiKey})
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create pinecone client", err.Error())
 		return
 	}
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	readOnly, diags := boolFromEnv(data.ReadOnly, "PINECONE_READ_ONLY")
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ValidateCredentials.ValueBool() || data.ExpectedProjectId.ValueString() != "" {
+		resp.Diagnostics.Append(validateCredentials(ctx, defaultClient, data.ExpectedProjectId.ValueString())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	providerData := &PineconeProviderData{
+		Client:   defaultClient,
+		Clients:  clients,
+		ReadOnly: readOnly,
+		Waiter:   waiterConfig,
+		Timeouts: defaultTimeouts,
+		Policy:   policy,
+		Costs:    costs,
+	}
+
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// newCredentials picks the source of the API key. Credentials in the configuration take
+// precedence over the environment variables, which take precedence over the default profile.
+func newCredentials(ctx context.Context, data *PineconeProviderModel) (credentials, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	creds := credentials{
+		apiKey:  data.ApiKey.ValueString(),
+		file:    data.ApiKeyFile.ValueString(),
+		profile: data.Profile.ValueString(),
+	}
+	if !data.ApiKeyCommand.IsNull() && !data.ApiKeyCommand.IsUnknown() {
+		diags.Append(data.ApiKeyCommand.ElementsAs(ctx, &creds.command, false)...)
+	}
+	if creds.isSet() || diags.HasError() {
+		return creds, diags
+	}
+
+	creds = credentials{
+		apiKey:  os.Getenv("PINECONE_API_KEY"),
+		file:    os.Getenv("PINECONE_API_KEY_FILE"),
+		profile: os.Getenv("PINECONE_PROFILE"),
+	}
+	if !creds.isSet() && hasProfile(defaultProfile) {
+		creds.profile = defaultProfile
+	}
+	return creds, diags
+}
+
+// newRetryPolicy overlays the retry settings from the provider configuration on the defaults.
+func newRetryPolicy(data *PineconeProviderModel) (client.RetryPolicy, diag.Diagnostics) {
+	policy := client.DefaultRetryPolicy()
+
+	if !data.MaxRetries.IsNull() && !data.MaxRetries.IsUnknown() {
+		policy.MaxRetries = int(data.MaxRetries.ValueInt64())
+	}
+	diags := parseDurations(path.Empty(), map[string]durationAttribute{
+		"retry_min_backoff": {data.RetryMinBackoff, &policy.MinBackoff},
+		"retry_max_backoff": {data.RetryMaxBackoff, &policy.MaxBackoff},
+	})
+	if diags.HasError() {
+		return policy, diags
+	}
+
+	if err := policy.Validate(); err != nil {
+		diags.AddAttributeError(path.Root("retry_max_backoff"), "Invalid retry configuration", err.Error())
+	}
+	return policy, diags
+}
+
+// newLimiter builds the limiter shared by all requests of the provider, or nil when
+// neither max_concurrent_requests nor requests_per_second is set.
+func newLimiter(data *PineconeProviderModel) (*client.Limiter, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	maxConcurrent := 0
+	if !data.MaxConcurrentRequests.IsNull() && !data.MaxConcurrentRequests.IsUnknown() {
+		maxConcurrent = int(data.MaxConcurrentRequests.ValueInt64())
+	}
+	requestsPerSecond := 0.0
+	if !data.RequestsPerSecond.IsNull() && !data.RequestsPerSecond.IsUnknown() {
+		requestsPerSecond = data.RequestsPerSecond.ValueFloat64()
+		if requestsPerSecond <= 0 {
+			diags.AddAttributeError(path.Root("requests_per_second"), "Invalid rate limit", "requests_per_second must be greater than zero.")
+			return nil, diags
+		}
+	}
+	return client.NewLimiter(maxConcurrent, requestsPerSecond), diags
+}
+
+// configureHTTP reads the proxy, certificate authorities and headers of the provider
+// configuration, or of the environment variables that stand in for them, into options.
+func configureHTTP(ctx context.Context, data *PineconeProviderModel, options *client.Options) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if proxy := stringFromEnv(data.ProxyUrl, "PINECONE_PROXY_URL"); proxy != "" {
+		parsed, err := url.Parse(proxy)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			diags.AddAttributeError(path.Root("proxy_url"), "Invalid proxy URL", fmt.Sprintf("Expected an absolute URL such as http://proxy.example.com:3128, got %q.", proxy))
+		} else {
+			options.Proxy = parsed
+		}
+	}
+
+	if file := stringFromEnv(data.CaBundleFile, "PINECONE_CA_BUNDLE_FILE"); file != "" {
+		pool, err := loadCABundle(file)
+		if err != nil {
+			diags.AddAttributeError(path.Root("ca_bundle_file"), "Invalid CA bundle", err.Error())
+		} else {
+			options.RootCAs = pool
+		}
+	}
+
+	headers := http.Header{}
+	if env := os.Getenv("PINECONE_HEADERS"); env != "" {
+		for _, pair := range strings.Split(env, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok || strings.TrimSpace(name) == "" {
+				diags.AddAttributeError(path.Root("headers"), "Invalid headers",
+					fmt.Sprintf("PINECONE_HEADERS must be a comma-separated list of name=value pairs, got %q.", pair))
+				continue
+			}
+			headers.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+	if !data.Headers.IsNull() && !data.Headers.IsUnknown() {
+		var configured map[string]string
+		diags.Append(data.Headers.ElementsAs(ctx, &configured, false)...)
+		for name, value := range configured {
+			headers.Set(name, value)
+		}
+	}
+	if len(headers) > 0 {
+		options.Headers = headers
+	}
+	return diags
+}
+
+// loadCABundle returns the system certificate pool extended with the certificates in file.
+func loadCABundle(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no PEM encoded certificates found in %s", file)
+	}
+	return pool, nil
+}
+
+// userAgent identifies the provider and Terraform versions in requests to the API.
+func userAgent(providerVersion string, terraformVersion string, extra string) string {
+	if terraformVersion == "" {
+		terraformVersion = "unknown"
+	}
+	ua := fmt.Sprintf("terraform-provider-pinecone/%s Terraform/%s", providerVersion, terraformVersion)
+	if extra = strings.TrimSpace(extra); extra != "" {
+		ua += " " + extra
+	}
+	return ua
+}
+
+// stringFromEnv returns the configured value of attr, or the value of the environment
+// variable env when it is not set.
+func stringFromEnv(attr types.String, env string) string {
+	if !attr.IsNull() && !attr.IsUnknown() {
+		return attr.ValueString()
+	}
+	return os.Getenv(env)
+}
+
+// boolFromEnv returns the configured value of attr, or the value of the environment
+// variable env when it is not set.
+func boolFromEnv(attr types.Bool, env string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !attr.IsNull() && !attr.IsUnknown() {
+		return attr.ValueBool(), diags
+	}
+	value := os.Getenv(env)
+	if value == "" {
+		return false, diags
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		diags.AddError("Invalid environment variable", fmt.Sprintf("%s must be true or false, got %q.", env, value))
+	}
+	return parsed, diags
+}
+
+// newWaiterConfig overlays the waiter settings from the provider configuration on the defaults.
+func newWaiterConfig(ctx context.Context, obj types.Object) (waiter.Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	config := waiter.DefaultConfig()
+	if obj.IsNull() || obj.IsUnknown() {
+		return config, diags
+	}
+
+	var model PineconeWaiterModel
+	diags.Append(obj.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return config, diags
+	}
+
+	diags.Append(parseDurations(path.Root("waiter"), map[string]durationAttribute{
+		"poll_interval": {model.PollInterval, &config.PollInterval},
+		"min_backoff":   {model.MinBackoff, &config.MinBackoff},
+		"max_backoff":   {model.MaxBackoff, &config.MaxBackoff},
+	})...)
+	if !model.Jitter.IsNull() && !model.Jitter.IsUnknown() {
+		config.Jitter = model.Jitter.ValueFloat64()
+	}
+	if diags.HasError() {
+		return config, diags
+	}
+
+	if err := config.Validate(); err != nil {
+		diags.AddAttributeError(path.Root("waiter"), "Invalid waiter configuration", err.Error())
+	}
+	return config, diags
+}
+
+// newDefaultTimeouts reads the provider-level overrides for resource timeouts.
+func newDefaultTimeouts(ctx context.Context, obj types.Object) (DefaultTimeouts, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var defaults DefaultTimeouts
+
+	if obj.IsNull() || obj.IsUnknown() {
+		return defaults, diags
+	}
+
+	var model PineconeTimeoutsModel
+	diags.Append(obj.As(ctx, &model, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return defaults, diags
+	}
+
+	diags.Append(parseDurations(path.Root("default_timeouts"), map[string]durationAttribute{
+		"create": {model.Create, &defaults.Create},
+		"read":   {model.Read, &defaults.Read},
+		"update": {model.Update, &defaults.Update},
+		"delete": {model.Delete, &defaults.Delete},
+	})...)
+	return defaults, diags
+}
+
+type durationAttribute struct {
+	value  types.String
+	target *time.Duration
+}
+
+// parseDurations parses every known duration attribute nested under base into its target.
+func parseDurations(base path.Path, attrs map[string]durationAttribute) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for name, attr := range attrs {
+		if attr.value.IsNull() || attr.value.IsUnknown() {
+			continue
+		}
+		parsed, err := time.ParseDuration(attr.value.ValueString())
+		if err != nil {
+			diags.AddAttributeError(base.AtName(name), "Invalid duration", err.Error())
+			continue
+		}
+		if parsed <= 0 {
+			diags.AddAttributeError(base.AtName(name), "Invalid duration", "Duration must be greater than zero.")
+			continue
+		}
+		*attr.target = parsed
+	}
+	return diags
 }
 
 func (p *PineconeProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCollectionResource,
 		NewIndexResource,
+		NewIndexMigrationResource,
+		NewIndexCopyResource,
 	}
 }
 
 func (p *PineconeProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
+		NewCallerIdentityDataSource,
 		NewCollectionsDataSource,
 		NewCollectionDataSource,
 		NewIndexesDataSource,
@@ -93,6 +677,15 @@ func (p *PineconeProvider) DataSources(ctx context.Context) []func() datasource.
 	}
 }
 
+func (p *PineconeProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewEstimateStorageFunction,
+		NewIndexHostPartsFunction,
+		NewParsePodTypeFunction,
+		NewPodCountFunction,
+	}
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &PineconeProvider{
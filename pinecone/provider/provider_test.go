@@ -4,10 +4,16 @@
 package provider
 
 import (
+	"context"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -23,3 +29,159 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+func TestNewRetryPolicy(t *testing.T) {
+	cases := map[string]struct {
+		data  PineconeProviderModel
+		want  client.RetryPolicy
+		valid bool
+	}{
+		"defaults": {
+			data:  PineconeProviderModel{MaxRetries: types.Int64Null(), RetryMinBackoff: types.StringNull(), RetryMaxBackoff: types.StringNull()},
+			want:  client.DefaultRetryPolicy(),
+			valid: true,
+		},
+		"overridden": {
+			data:  PineconeProviderModel{MaxRetries: types.Int64Value(0), RetryMinBackoff: types.StringValue("200ms"), RetryMaxBackoff: types.StringValue("5s")},
+			want:  client.RetryPolicy{MaxRetries: 0, MinBackoff: 200 * time.Millisecond, MaxBackoff: 5 * time.Second},
+			valid: true,
+		},
+		"invalid duration": {
+			data: PineconeProviderModel{MaxRetries: types.Int64Null(), RetryMinBackoff: types.StringValue("soon"), RetryMaxBackoff: types.StringNull()},
+		},
+		"max below min": {
+			data: PineconeProviderModel{MaxRetries: types.Int64Null(), RetryMinBackoff: types.StringValue("1m"), RetryMaxBackoff: types.StringValue("1s")},
+		},
+	}
+
+	for name, c := range cases {
+		got, diags := newRetryPolicy(&c.data)
+		if diags.HasError() == c.valid {
+			t.Errorf("%s: expected valid %t, got: %v", name, c.valid, diags)
+			continue
+		}
+		if c.valid && got != c.want {
+			t.Errorf("%s: expected %+v, got %+v", name, c.want, got)
+		}
+	}
+}
+
+func TestNewLimiter(t *testing.T) {
+	cases := map[string]struct {
+		data    PineconeProviderModel
+		limited bool
+		valid   bool
+	}{
+		"unlimited": {
+			data:  PineconeProviderModel{MaxConcurrentRequests: types.Int64Null(), RequestsPerSecond: types.Float64Null()},
+			valid: true,
+		},
+		"concurrency": {
+			data:    PineconeProviderModel{MaxConcurrentRequests: types.Int64Value(4), RequestsPerSecond: types.Float64Null()},
+			limited: true,
+			valid:   true,
+		},
+		"rate": {
+			data:    PineconeProviderModel{MaxConcurrentRequests: types.Int64Null(), RequestsPerSecond: types.Float64Value(0.5)},
+			limited: true,
+			valid:   true,
+		},
+		"zero rate": {
+			data: PineconeProviderModel{MaxConcurrentRequests: types.Int64Null(), RequestsPerSecond: types.Float64Value(0)},
+		},
+	}
+
+	for name, c := range cases {
+		got, diags := newLimiter(&c.data)
+		if diags.HasError() == c.valid {
+			t.Errorf("%s: expected valid %t, got: %v", name, c.valid, diags)
+			continue
+		}
+		if (got != nil) != c.limited {
+			t.Errorf("%s: expected limited %t, got %+v", name, c.limited, got)
+		}
+	}
+}
+
+func TestConfigureHTTP(t *testing.T) {
+	t.Setenv("PINECONE_PROXY_URL", "http://env-proxy.example.com:3128")
+	t.Setenv("PINECONE_CA_BUNDLE_FILE", "")
+	t.Setenv("PINECONE_HEADERS", "X-Gateway=env, X-Team=search")
+
+	headers := types.MapValueMust(types.StringType, map[string]attr.Value{"X-Gateway": types.StringValue("config")})
+	data := PineconeProviderModel{ProxyUrl: types.StringNull(), CaBundleFile: types.StringNull(), Headers: headers}
+
+	var options client.Options
+	if diags := configureHTTP(context.Background(), &data, &options); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if options.Proxy == nil || options.Proxy.Host != "env-proxy.example.com:3128" {
+		t.Errorf("expected the proxy from PINECONE_PROXY_URL, got %v", options.Proxy)
+	}
+	if options.RootCAs != nil {
+		t.Error("expected the system certificate authorities")
+	}
+	if got := options.Headers.Get("X-Gateway"); got != "config" {
+		t.Errorf("expected the configured header to take precedence, got %q", got)
+	}
+	if got := options.Headers.Get("X-Team"); got != "search" {
+		t.Errorf("expected the header from PINECONE_HEADERS, got %q", got)
+	}
+}
+
+func TestConfigureHTTP_invalid(t *testing.T) {
+	t.Setenv("PINECONE_HEADERS", "")
+	empty := writeTestFile(t, "empty.pem", "not a certificate")
+
+	cases := map[string]PineconeProviderModel{
+		"relative proxy":    {ProxyUrl: types.StringValue("proxy.example.com"), CaBundleFile: types.StringNull(), Headers: types.MapNull(types.StringType)},
+		"missing ca bundle": {ProxyUrl: types.StringNull(), CaBundleFile: types.StringValue(filepath.Join(t.TempDir(), "missing.pem")), Headers: types.MapNull(types.StringType)},
+		"empty ca bundle":   {ProxyUrl: types.StringNull(), CaBundleFile: types.StringValue(empty), Headers: types.MapNull(types.StringType)},
+	}
+
+	for name, data := range cases {
+		var options client.Options
+		if diags := configureHTTP(context.Background(), &data, &options); !diags.HasError() {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	t.Setenv("PINECONE_HEADERS", "X-Gateway")
+	var options client.Options
+	data := PineconeProviderModel{ProxyUrl: types.StringNull(), CaBundleFile: types.StringNull(), Headers: types.MapNull(types.StringType)}
+	if diags := configureHTTP(context.Background(), &data, &options); !diags.HasError() {
+		t.Error("expected an error for a header without a value")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	cases := map[string]struct {
+		provider, terraform, extra string
+		want                       string
+	}{
+		"versions": {"1.2.0", "1.8.2", "", "terraform-provider-pinecone/1.2.0 Terraform/1.8.2"},
+		"unknown":  {"dev", "", "", "terraform-provider-pinecone/dev Terraform/unknown"},
+		"appended": {"1.2.0", "1.8.2", " ci/42 ", "terraform-provider-pinecone/1.2.0 Terraform/1.8.2 ci/42"},
+	}
+
+	for name, c := range cases {
+		if got := userAgent(c.provider, c.terraform, c.extra); got != c.want {
+			t.Errorf("%s: expected %q, got %q", name, c.want, got)
+		}
+	}
+}
+
+func TestBoolFromEnv(t *testing.T) {
+	t.Setenv("PINECONE_READ_ONLY", "true")
+	if got, _ := boolFromEnv(types.BoolNull(), "PINECONE_READ_ONLY"); !got {
+		t.Error("expected the environment variable to be used when the attribute is not set")
+	}
+	if got, _ := boolFromEnv(types.BoolValue(false), "PINECONE_READ_ONLY"); got {
+		t.Error("expected the attribute to take precedence over the environment variable")
+	}
+
+	t.Setenv("PINECONE_READ_ONLY", "sometimes")
+	if _, diags := boolFromEnv(types.BoolNull(), "PINECONE_READ_ONLY"); !diags.HasError() {
+		t.Error("expected an error for a value that is not a boolean")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/models"
+)
+
+// indexReplacement is a planned change that forces an index to be replaced.
+type indexReplacement struct {
+	path     path.Path
+	from, to string
+}
+
+// indexReplacements lists the changes from the prior to the planned index that force it to
+// be replaced, one for every attribute with a RequiresReplace plan modifier. Unknown planned
+// values are not compared. Changed credentials only replace the index when they belong to
+// another project, see credentialChanges.
+func indexReplacements(ctx context.Context, prior *models.IndexResourceModel, planned *models.IndexResourceModel) ([]indexReplacement, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var replacements []indexReplacement
+
+	compare := func(p path.Path, from attr.Value, to attr.Value) {
+		if !to.IsUnknown() && !from.Equal(to) {
+			replacements = append(replacements, indexReplacement{path: p, from: from.String(), to: to.String()})
+		}
+	}
+	compare(path.Root("name"), prior.Name, planned.Name)
+	compare(path.Root("dimension"), prior.Dimension, planned.Dimension)
+	compare(path.Root("metric"), prior.Metric, planned.Metric)
+
+	if prior.Spec.IsNull() || planned.Spec.IsNull() || planned.Spec.IsUnknown() {
+		return replacements, diags
+	}
+	var priorSpec, plannedSpec models.IndexSpecModel
+	diags.Append(prior.Spec.As(ctx, &priorSpec, basetypes.ObjectAsOptions{})...)
+	diags.Append(planned.Spec.As(ctx, &plannedSpec, basetypes.ObjectAsOptions{})...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	switch spec := path.Root("spec"); {
+	case priorSpec.Pod != nil && plannedSpec.Pod != nil:
+		pod := spec.AtName("pod")
+		compare(pod.AtName("environment"), priorSpec.Pod.Environment, plannedSpec.Pod.Environment)
+		compare(pod.AtName("replicas"), priorSpec.Pod.Replicas, plannedSpec.Pod.Replicas)
+		compare(pod.AtName("shards"), priorSpec.Pod.ShardCount, plannedSpec.Pod.ShardCount)
+		compare(pod.AtName("pod_type"), priorSpec.Pod.PodType, plannedSpec.Pod.PodType)
+		compare(pod.AtName("source_collection"), priorSpec.Pod.SourceCollection, plannedSpec.Pod.SourceCollection)
+	case priorSpec.Serverless != nil && plannedSpec.Serverless != nil:
+		compare(spec.AtName("serverless").AtName("cloud"), priorSpec.Serverless.Cloud, plannedSpec.Serverless.Cloud)
+		compare(spec.AtName("serverless").AtName("region"), priorSpec.Serverless.Region, plannedSpec.Serverless.Region)
+	default:
+		replacements = append(replacements, indexReplacement{path: spec, from: specKind(priorSpec), to: specKind(plannedSpec)})
+	}
+	return replacements, diags
+}
+
+// credentialChanges lists the changes of the credentials from the prior to the planned
+// index. Unknown planned values are not compared.
+func credentialChanges(prior *models.IndexResourceModel, planned *models.IndexResourceModel) []indexReplacement {
+	var changes []indexReplacement
+	if !planned.Profile.IsUnknown() && !prior.Profile.Equal(planned.Profile) {
+		changes = append(changes, indexReplacement{path: path.Root("profile"), from: prior.Profile.String(), to: planned.Profile.String()})
+	}
+	if !planned.ProjectApiKey.IsUnknown() && !prior.ProjectApiKey.Equal(planned.ProjectApiKey) {
+		// The key itself must not show up in the plan output.
+		changes = append(changes, indexReplacement{path: path.Root("project_api_key"), from: sensitiveString(prior.ProjectApiKey), to: sensitiveString(planned.ProjectApiKey)})
+	}
+	return changes
+}
+
+// sensitiveString stands in for the value of a sensitive attribute in messages.
+func sensitiveString(v types.String) string {
+	if v.IsNull() {
+		return "<null>"
+	}
+	return "(sensitive value)"
+}
+
+func specKind(spec models.IndexSpecModel) string {
+	if spec.Pod != nil {
+		return "pod"
+	}
+	return "serverless"
+}
+
+// warnReplacement warns about every change that forces the index to be replaced, together
+// with the number of vectors that would be lost. Changed credentials replace the index when
+// they belong to another project, and are otherwise warned about as used in place.
+func (r *IndexResource) warnReplacement(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse, planned *models.IndexResourceModel) {
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var prior models.IndexResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	replacements, diags := indexReplacements(ctx, &prior, planned)
+	resp.Diagnostics.Append(diags...)
+	if credentials := credentialChanges(&prior, planned); len(credentials) > 0 {
+		switch change := r.credentialsChange(ctx, req.State, resp.Plan); {
+		case change.known && change.from != change.to:
+			replacements = append(replacements, credentials...)
+		case change.known:
+			for _, c := range credentials {
+				resp.Diagnostics.AddAttributeWarning(c.path, "Index credentials changed",
+					fmt.Sprintf("The new credentials belong to project %s, like the old ones, so index %s is kept and only its credentials are updated.",
+						change.to, prior.Name.ValueString()))
+			}
+		default:
+			for _, c := range credentials {
+				resp.Diagnostics.AddAttributeWarning(c.path, "Index credentials changed",
+					fmt.Sprintf("The projects of the credentials could not be resolved, so index %s is kept and only its credentials are updated. "+
+						"If the new credentials belong to another project, the index is not found there and the next refresh removes it from state.",
+						prior.Name.ValueString()))
+			}
+		}
+	}
+	if len(replacements) == 0 {
+		return
+	}
+
+	vectors := "an unknown number of vectors"
+	if api, diags := r.clientFor(ctx, prior.ProjectApiKey, prior.Profile); !diags.HasError() && prior.Host.ValueString() != "" {
+		count, err := indexVectorCount(ctx, api, prior.Host.ValueString())
+		if err != nil {
+			tflog.Warn(ctx, "failed to count the vectors of an index to be replaced", map[string]interface{}{
+				"index": prior.Name.ValueString(),
+				"error": err.Error(),
+			})
+		} else {
+			vectors = fmt.Sprintf("%d vectors", count)
+		}
+	}
+
+	for _, c := range replacements {
+		resp.Diagnostics.AddAttributeWarning(c.path, "Index will be replaced",
+			fmt.Sprintf("Changing %s from %s to %s forces index %s to be destroyed and created again. "+
+				"The index holds %s, and all data in it will be lost.", c.path, c.from, c.to, prior.Name.ValueString(), vectors))
+	}
+}
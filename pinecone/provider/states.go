@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+// deletedState is reported by delete waiters once the object can no longer be described.
+const deletedState = "Deleted"
+
+// classifyIndexCreateState classifies an index state while waiting for it to become ready.
+// Unknown states are treated as pending so that new transitional states added to the API
+// do not break existing waits.
+func classifyIndexCreateState(status *pinecone.IndexStatus) waiter.Class {
+	if status == nil {
+		return waiter.Pending
+	}
+	switch status.State {
+	case pinecone.InitializationFailed, pinecone.Terminating:
+		return waiter.Failed
+	case pinecone.Ready:
+		return waiter.Target
+	}
+	if status.Ready {
+		return waiter.Target
+	}
+	return waiter.Pending
+}
+
+// classifyCollectionCreateState classifies a collection state while waiting for it to become ready.
+func classifyCollectionCreateState(status pinecone.CollectionStatus) waiter.Class {
+	switch status {
+	case pinecone.CollectionStatusReady:
+		return waiter.Target
+	case pinecone.CollectionStatusTerminating:
+		return waiter.Failed
+	}
+	return waiter.Pending
+}
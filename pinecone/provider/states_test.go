@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/waiter"
+)
+
+func TestClassifyIndexCreateState(t *testing.T) {
+	cases := map[string]struct {
+		status *pinecone.IndexStatus
+		want   waiter.Class
+	}{
+		"nil":            {nil, waiter.Pending},
+		"initializing":   {&pinecone.IndexStatus{State: pinecone.Initializing}, waiter.Pending},
+		"scaling":        {&pinecone.IndexStatus{State: pinecone.ScalingUp}, waiter.Pending},
+		"unknown":        {&pinecone.IndexStatus{State: "Upgrading"}, waiter.Pending},
+		"ready":          {&pinecone.IndexStatus{Ready: true, State: pinecone.Ready}, waiter.Target},
+		"ready flag":     {&pinecone.IndexStatus{Ready: true, State: "Upgrading"}, waiter.Target},
+		"failed":         {&pinecone.IndexStatus{State: pinecone.InitializationFailed}, waiter.Failed},
+		"terminating":    {&pinecone.IndexStatus{State: pinecone.Terminating}, waiter.Failed},
+		"ready flag set": {&pinecone.IndexStatus{Ready: true, State: pinecone.Terminating}, waiter.Failed},
+	}
+
+	for name, c := range cases {
+		if got := classifyIndexCreateState(c.status); got != c.want {
+			t.Errorf("%s: expected %d, got %d", name, c.want, got)
+		}
+	}
+}
+
+func TestClassifyCollectionCreateState(t *testing.T) {
+	cases := map[pinecone.CollectionStatus]waiter.Class{
+		pinecone.CollectionStatusInitializing: waiter.Pending,
+		pinecone.CollectionStatusReady:        waiter.Target,
+		pinecone.CollectionStatusTerminating:  waiter.Failed,
+	}
+
+	for status, want := range cases {
+		if got := classifyCollectionCreateState(status); got != want {
+			t.Errorf("%s: expected %d, got %d", status, want, got)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pinecone-io/go-pinecone/pinecone"
+	"github.com/pinecone-io/terraform-provider-pinecone/pinecone/client"
+)
+
+// copyCheckpoint is where a vector copy resumes. The namespaces before Namespace have
+// been copied, and Namespace continues after PaginationToken, or from its first page
+// when it is nil.
+type copyCheckpoint struct {
+	Namespace       string
+	PaginationToken *string
+}
+
+// vectorCopy copies vectors between the data planes of two indexes. Namespaces are
+// copied one after another, in order. Within a namespace, up to Concurrency pages of
+// ids are listed, then fetched and upserted concurrently.
+type vectorCopy struct {
+	Source client.DataPlane
+	Target client.DataPlane
+	// Namespaces holds the namespaces to copy, in lexical order.
+	Namespaces []string
+	// Prefix, when set, restricts the copy to the ids that start with it.
+	Prefix *string
+	// Match, when set, restricts the copy to the vectors whose metadata matches it.
+	Match       metadataPredicate
+	BatchSize   uint32
+	Concurrency int
+	// Added counts, per namespace, the upserted vectors whose ids the target index did
+	// not hold yet. Run fills it in.
+	Added map[string]int64
+}
+
+// Run copies the vectors from checkpoint on, or from the start when checkpoint is nil.
+// After every round of pages it calls progress with the checkpoint to resume from and
+// the number of vectors upserted in that round. The checkpoint is nil once the copy is
+// complete. An error leaves the copy at the last reported checkpoint.
+func (c *vectorCopy) Run(ctx context.Context, checkpoint *copyCheckpoint, progress func(checkpoint *copyCheckpoint, copied int64)) error {
+	for i, namespace := range c.Namespaces {
+		var token *string
+		if checkpoint != nil {
+			if namespace < checkpoint.Namespace {
+				continue
+			}
+			if namespace == checkpoint.Namespace {
+				token = checkpoint.PaginationToken
+			}
+		}
+
+		for {
+			var pages [][]string
+			var err error
+			for len(pages) < c.Concurrency {
+				var ids []string
+				ids, token, err = c.list(ctx, namespace, token)
+				if err != nil {
+					return err
+				}
+				if len(ids) > 0 {
+					pages = append(pages, ids)
+				}
+				if token == nil {
+					break
+				}
+			}
+
+			copied, added, err := c.copyPages(ctx, namespace, pages)
+			if c.Added == nil {
+				c.Added = map[string]int64{}
+			}
+			c.Added[namespace] += added
+			if err != nil {
+				return err
+			}
+			if token != nil {
+				progress(&copyCheckpoint{Namespace: namespace, PaginationToken: token}, copied)
+				continue
+			}
+			if i+1 < len(c.Namespaces) {
+				progress(&copyCheckpoint{Namespace: c.Namespaces[i+1]}, copied)
+			} else {
+				progress(nil, copied)
+			}
+			break
+		}
+	}
+	if len(c.Namespaces) == 0 {
+		progress(nil, 0)
+	}
+	return nil
+}
+
+// list returns a page of ids and the token of the next page, which is nil after the
+// last page.
+func (c *vectorCopy) list(ctx context.Context, namespace string, token *string) ([]string, *string, error) {
+	limit := c.BatchSize
+	resp, err := c.Source.ListVectors(ctx, namespace, &pinecone.ListVectorsRequest{
+		Prefix:          c.Prefix,
+		Limit:           &limit,
+		PaginationToken: token,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list vectors in namespace %q: %w", namespace, err)
+	}
+	var ids []string
+	for _, id := range resp.VectorIds {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	next := resp.NextPaginationToken
+	if next != nil && *next == "" {
+		next = nil
+	}
+	return ids, next, nil
+}
+
+// copyPages fetches and upserts the pages concurrently. It returns the number of
+// vectors upserted, how many of them were new to the target index, and the first
+// error.
+func (c *vectorCopy) copyPages(ctx context.Context, namespace string, pages [][]string) (int64, int64, error) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var copied, added int64
+	var firstErr error
+
+	for _, ids := range pages {
+		wg.Add(1)
+		go func(ids []string) {
+			defer wg.Done()
+			n, m, err := c.copyPage(ctx, namespace, ids)
+
+			mu.Lock()
+			defer mu.Unlock()
+			copied += n
+			added += m
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(ids)
+	}
+	wg.Wait()
+	return copied, added, firstErr
+}
+
+// copyPage copies the vectors of ids, and returns the number of vectors upserted and
+// how many of them were new to the target index.
+func (c *vectorCopy) copyPage(ctx context.Context, namespace string, ids []string) (int64, int64, error) {
+	resp, err := c.Source.FetchVectors(ctx, namespace, ids)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch vectors in namespace %q: %w", namespace, err)
+	}
+
+	// Upsert in the order the ids were listed, which keeps requests reproducible.
+	var vectors []*pinecone.Vector
+	for _, id := range ids {
+		vector, ok := resp.Vectors[id]
+		if !ok || vector == nil {
+			// Deleted since it was listed.
+			continue
+		}
+		if c.Match != nil && !matchMetadata(c.Match, vector) {
+			continue
+		}
+		vectors = append(vectors, vector)
+	}
+	if len(vectors) == 0 {
+		return 0, 0, nil
+	}
+
+	// Upserting an id the target already holds overwrites it, which does not add to
+	// the vector count the copy is verified against.
+	upserted := make([]string, len(vectors))
+	for i, vector := range vectors {
+		upserted[i] = vector.Id
+	}
+	existing, err := c.Target.FetchVectors(ctx, namespace, upserted)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fetch vectors of the target index in namespace %q: %w", namespace, err)
+	}
+	added := int64(len(vectors) - len(existing.Vectors))
+
+	if _, err := c.Target.UpsertVectors(ctx, namespace, vectors); err != nil {
+		return 0, 0, fmt.Errorf("failed to upsert vectors in namespace %q: %w", namespace, err)
+	}
+	return int64(len(vectors)), added, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package waiter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Timeline records how long an object spent in each observed state.
+type Timeline struct {
+	start    time.Time
+	last     string
+	lastSeen time.Time
+	order    []string
+	spent    map[string]time.Duration
+	now      func() time.Time
+}
+
+func NewTimeline() *Timeline {
+	return newTimeline(time.Now)
+}
+
+func newTimeline(now func() time.Time) *Timeline {
+	t := &Timeline{
+		spent: map[string]time.Duration{},
+		now:   now,
+	}
+	t.start = t.now()
+	t.lastSeen = t.start
+	return t
+}
+
+// Observe records a new observation of the given state.
+func (t *Timeline) Observe(state string) {
+	now := t.now()
+	if t.last != "" {
+		t.spent[t.last] += now.Sub(t.lastSeen)
+	}
+	if _, ok := t.spent[state]; !ok {
+		t.order = append(t.order, state)
+		t.spent[state] = 0
+	}
+	t.last = state
+	t.lastSeen = now
+}
+
+// Last returns the last observed state, or an empty string if nothing was observed.
+func (t *Timeline) Last() string {
+	return t.last
+}
+
+// Elapsed returns the time since the timeline was started.
+func (t *Timeline) Elapsed() time.Duration {
+	return t.now().Sub(t.start)
+}
+
+// String summarises the total wait and the time spent in each state, in the order
+// the states were first observed.
+func (t *Timeline) String() string {
+	now := t.now()
+	parts := make([]string, 0, len(t.order))
+	for _, state := range t.order {
+		spent := t.spent[state]
+		if state == t.last {
+			spent += now.Sub(t.lastSeen)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", state, spent.Round(time.Second)))
+	}
+	return fmt.Sprintf("waited %s (%s)", now.Sub(t.start).Round(time.Second), strings.Join(parts, ", "))
+}
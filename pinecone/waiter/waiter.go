@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package waiter polls Pinecone objects until they reach a target state.
+package waiter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	DefaultPollInterval time.Duration = 5 * time.Second
+	DefaultMinBackoff   time.Duration = 1 * time.Second
+	DefaultMaxBackoff   time.Duration = 30 * time.Second
+	DefaultJitter       float64       = 0.1
+)
+
+// Class classifies a remote state observed while waiting.
+type Class int
+
+const (
+	// Pending means the object is still transitioning and we should keep waiting.
+	Pending Class = iota
+	// Target means the object reached the state we were waiting for.
+	Target
+	// Failed means the object can never reach the target state.
+	Failed
+)
+
+// Config controls how often a Waiter polls. The delay starts at PollInterval, doubles
+// within [MinBackoff, MaxBackoff] while the state is unchanged, and resets on every
+// state change. Jitter randomises each delay by that fraction.
+type Config struct {
+	PollInterval time.Duration
+	MinBackoff   time.Duration
+	MaxBackoff   time.Duration
+	Jitter       float64
+}
+
+// DefaultConfig returns the configuration used when the provider does not override it.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: DefaultPollInterval,
+		MinBackoff:   DefaultMinBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		Jitter:       DefaultJitter,
+	}
+}
+
+// Validate reports configuration values that would make polling misbehave.
+func (c Config) Validate() error {
+	switch {
+	case c.PollInterval <= 0:
+		return errors.New("poll_interval must be greater than zero")
+	case c.MinBackoff <= 0:
+		return errors.New("min_backoff must be greater than zero")
+	case c.MaxBackoff < c.MinBackoff:
+		return errors.New("max_backoff must be greater than or equal to min_backoff")
+	case c.Jitter < 0 || c.Jitter > 1:
+		return errors.New("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// RefreshFunc fetches the current state of the object and classifies it.
+type RefreshFunc func(ctx context.Context) (state string, class Class, err error)
+
+// Waiter polls an object until it reaches a target state, a failed state or the timeout.
+type Waiter struct {
+	Config
+
+	// Kind and Name identify the object in log messages and errors, e.g. "index" and "my-index".
+	Kind string
+	Name string
+
+	// Refresh fetches the current state of the object.
+	Refresh RefreshFunc
+
+	// Timeout bounds the total wait. Zero means the wait is only bounded by the context.
+	Timeout time.Duration
+
+	// sleep is overridden in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// FailedStateError is returned when the object enters a state it cannot recover from.
+type FailedStateError struct {
+	Kind     string
+	Name     string
+	State    string
+	Timeline *Timeline
+}
+
+func (e *FailedStateError) Error() string {
+	return fmt.Sprintf("%s %s entered terminal state %s; %s", e.Kind, e.Name, e.State, e.Timeline)
+}
+
+// TimeoutError is returned when the object did not reach a target state in time.
+type TimeoutError struct {
+	Kind      string
+	Name      string
+	LastState string
+	Timeline  *Timeline
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timeout while waiting for %s %s, last state: %s; %s", e.Kind, e.Name, e.LastState, e.Timeline)
+}
+
+// Wait polls until the object reaches a target state and returns that state.
+func (w *Waiter) Wait(ctx context.Context) (string, error) {
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+
+	sleep := w.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	ctx = tflog.SetField(ctx, "pinecone_kind", w.Kind)
+	ctx = tflog.SetField(ctx, "pinecone_name", w.Name)
+
+	timeline := NewTimeline()
+	delay := w.PollInterval
+	for {
+		state, class, err := w.Refresh(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return timeline.Last(), w.stopped(ctx.Err(), timeline)
+			}
+			return timeline.Last(), err
+		}
+
+		previous := timeline.Last()
+		timeline.Observe(state)
+		if state != previous {
+			tflog.Info(ctx, fmt.Sprintf("%s %s state changed", w.Kind, w.Name), map[string]interface{}{
+				"from":    previous,
+				"to":      state,
+				"elapsed": timeline.Elapsed().Round(time.Second).String(),
+			})
+			delay = w.PollInterval
+		}
+
+		switch class {
+		case Target:
+			tflog.Info(ctx, fmt.Sprintf("%s %s reached %s", w.Kind, w.Name, state), map[string]interface{}{
+				"timeline": timeline.String(),
+			})
+			return state, nil
+		case Failed:
+			return state, &FailedStateError{Kind: w.Kind, Name: w.Name, State: state, Timeline: timeline}
+		}
+
+		wait := w.jitter(delay)
+		tflog.Debug(ctx, fmt.Sprintf("%s %s still %s", w.Kind, w.Name, state), map[string]interface{}{
+			"next_poll": wait.String(),
+		})
+		if err := sleep(ctx, wait); err != nil {
+			return state, w.stopped(err, timeline)
+		}
+		delay = w.nextDelay(delay)
+	}
+}
+
+// stopped returns the error of a wait cut short by err, the error of its context. A
+// cancelled context, such as an interrupted apply, is returned as is rather than
+// reported as a timeout.
+func (w *Waiter) stopped(err error, timeline *Timeline) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	return &TimeoutError{Kind: w.Kind, Name: w.Name, LastState: timeline.Last(), Timeline: timeline}
+}
+
+func (w *Waiter) nextDelay(delay time.Duration) time.Duration {
+	delay *= 2
+	if delay < w.MinBackoff {
+		delay = w.MinBackoff
+	}
+	if w.MaxBackoff > 0 && delay > w.MaxBackoff {
+		delay = w.MaxBackoff
+	}
+	return delay
+}
+
+func (w *Waiter) jitter(delay time.Duration) time.Duration {
+	if w.Jitter <= 0 {
+		return delay
+	}
+	// rand.Float64 is in [0, 1), scale it to [-Jitter, +Jitter).
+	factor := 1 + w.Jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(delay) * factor)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package waiter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type observation struct {
+	state string
+	class Class
+}
+
+func testWaiter(observations []observation, delays *[]time.Duration) *Waiter {
+	i := 0
+	return &Waiter{
+		Config: Config{
+			PollInterval: 2 * time.Second,
+			MinBackoff:   1 * time.Second,
+			MaxBackoff:   10 * time.Second,
+		},
+		Kind: "index",
+		Name: "test",
+		Refresh: func(ctx context.Context) (string, Class, error) {
+			o := observations[i]
+			if i < len(observations)-1 {
+				i++
+			}
+			return o.state, o.class, nil
+		},
+		sleep: func(ctx context.Context, d time.Duration) error {
+			*delays = append(*delays, d)
+			return nil
+		},
+	}
+}
+
+func TestWaiter_Target(t *testing.T) {
+	var delays []time.Duration
+	w := testWaiter([]observation{
+		{"Initializing", Pending},
+		{"Initializing", Pending},
+		{"Initializing", Pending},
+		{"Initializing", Pending},
+		{"ScalingUp", Pending},
+		{"Ready", Target},
+	}, &delays)
+
+	state, err := w.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %s", err)
+	}
+	if state != "Ready" {
+		t.Errorf("Expected state Ready, got: %s", state)
+	}
+
+	// Delays back off exponentially up to MaxBackoff and reset on a state change.
+	expected := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 2 * time.Second}
+	if len(delays) != len(expected) {
+		t.Fatalf("Expected delays %v, got: %v", expected, delays)
+	}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("Expected delays %v, got: %v", expected, delays)
+			break
+		}
+	}
+}
+
+func TestWaiter_Failed(t *testing.T) {
+	var delays []time.Duration
+	w := testWaiter([]observation{
+		{"Initializing", Pending},
+		{"InitializationFailed", Failed},
+		{"Ready", Target},
+	}, &delays)
+
+	state, err := w.Wait(context.Background())
+	var failed *FailedStateError
+	if !errors.As(err, &failed) {
+		t.Fatalf("Expected FailedStateError, got: %v", err)
+	}
+	if state != "InitializationFailed" || failed.State != "InitializationFailed" {
+		t.Errorf("Expected state InitializationFailed, got: %s", state)
+	}
+	if len(delays) != 1 {
+		t.Errorf("Expected the waiter to abort after the failed state, got %d polls", len(delays)+1)
+	}
+}
+
+func TestWaiter_Timeout(t *testing.T) {
+	var delays []time.Duration
+	w := testWaiter([]observation{{"Initializing", Pending}}, &delays)
+	w.sleep = func(ctx context.Context, d time.Duration) error {
+		return context.DeadlineExceeded
+	}
+
+	_, err := w.Wait(context.Background())
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("Expected TimeoutError, got: %v", err)
+	}
+	if timeout.LastState != "Initializing" {
+		t.Errorf("Expected last state Initializing, got: %s", timeout.LastState)
+	}
+}
+
+func TestWaiter_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var delays []time.Duration
+	w := testWaiter([]observation{{"Initializing", Pending}}, &delays)
+	w.sleep = func(ctx context.Context, d time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	if _, err := w.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation to be returned, got: %v", err)
+	}
+
+	w.Refresh = func(ctx context.Context) (string, Class, error) {
+		return "", Pending, ctx.Err()
+	}
+	if _, err := w.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation to be returned, got: %v", err)
+	}
+}
+
+func TestWaiter_RefreshError(t *testing.T) {
+	w := &Waiter{
+		Config: DefaultConfig(),
+		Refresh: func(ctx context.Context) (string, Class, error) {
+			return "", Pending, errors.New("boom")
+		},
+	}
+
+	if _, err := w.Wait(context.Background()); err == nil || err.Error() != "boom" {
+		t.Errorf("Expected refresh error, got: %v", err)
+	}
+}
+
+func TestWaiter_Jitter(t *testing.T) {
+	w := &Waiter{Config: Config{Jitter: 0.5}}
+	for i := 0; i < 100; i++ {
+		d := w.jitter(10 * time.Second)
+		if d < 5*time.Second || d > 15*time.Second {
+			t.Fatalf("Expected jittered delay within 50%% of 10s, got: %s", d)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("Expected default config to be valid, got: %s", err)
+	}
+
+	invalid := DefaultConfig()
+	invalid.MaxBackoff = invalid.MinBackoff / 2
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected an error when max_backoff is below min_backoff")
+	}
+
+	invalid = DefaultConfig()
+	invalid.Jitter = 2
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected an error when jitter is above 1")
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	now := time.Unix(0, 0)
+	timeline := newTimeline(func() time.Time { return now })
+
+	timeline.Observe("Initializing")
+	now = now.Add(30 * time.Second)
+	timeline.Observe("Initializing")
+	now = now.Add(30 * time.Second)
+	timeline.Observe("InitializationFailed")
+	now = now.Add(5 * time.Second)
+
+	if timeline.Last() != "InitializationFailed" {
+		t.Errorf("Expected last state InitializationFailed, got: %s", timeline.Last())
+	}
+
+	expected := "waited 1m5s (Initializing: 1m0s, InitializationFailed: 5s)"
+	if timeline.String() != expected {
+		t.Errorf("Expected %q, got: %q", expected, timeline.String())
+	}
+}